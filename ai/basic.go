@@ -2,7 +2,6 @@ package ai
 
 import (
     "sort"
-    "math/rand"
 
     "dr2w.com/hf/model/action"
     "dr2w.com/hf/model/state"
@@ -28,14 +27,14 @@ func last(s state.State, m action.Message) []int {
 
 // rand1 implements Decider and returns a random available option.
 func rand1(s state.State, m action.Message) []int {
-    return []int{m.Options[rand.Intn(len(m.Options))]}
+    return []int{m.Options[s.Rng().Intn(len(m.Options))]}
 }
 
 // randN generates a Decider that chooses N random elements from
 // the Options.
 func randN(n int) func(s state.State, m action.Message) []int {
     return func(s state.State, m action.Message) []int {
-        perm := rand.Perm(len(m.Options))
+        perm := s.Rng().Perm(len(m.Options))
         sort.Ints(perm)
         return perm[:n]
     }
@@ -47,7 +47,8 @@ func suit(f bidder) decider {
 // functions. If no max suit bid is >= 8, then it first
 // checks for a valid six bid. If there is not a valid
 // six bid, then it bids according to the min and max
-// of the suit with the highest max bid.
+// of the suit with the highest max bid. A stuck dealer,
+// who is not offered Pass, makes the lowest bid offered.
 // TODO(drw): consider adding in aggressiveness and
 // randomness as adjustments to min and max.
 // TODO(drw): deal with the no mans land between 10 and 14/28.
@@ -65,20 +66,57 @@ func fromBidders(forSuit forSuit, isSix isSix) func(s state.State, m action.Mess
 				bestSuit = suit
 			}
 		}
+		if bestSuit == card.NoSuit {
+			bestSuit = longest(card.Set(h))
+		}
 		_, currentBid := s.WinningBid()
-		if maxSuit < bid.B8 && currentBid == bid.Pass &&
+		if maxSuit < bid.B8 && currentBid == bid.Pass && offered(m, bid.B6) &&
 			isSix(card.Set(h)) {
 			// TODO(drw): handle the odd case where this wins.
 			// For now we just pick an arbitrary suit
 			return []int{int(bid.B6)}, card.NoSuit
 		}
 		for b := minSuit; b <= maxSuit; b++ {
-			if b > currentBid {
+			if b > currentBid && offered(m, b) {
 				return []int{int(b)}, bestSuit
 			}
 		}
-		return []int{int(bid.Pass)}, card.NoSuit
+		if !offered(m, bid.Pass) {
+			return []int{m.Options[0]}, bestSuit
+		}
+		return []int{int(bid.Pass)}, bestSuit
+	}
+}
+
+// longest returns the suit in which the given cards hold the most trump,
+// preferring the suit with the most trump points on a tie.
+func longest(cards card.Set) card.Suit {
+	best, bestLen, bestPoints := card.NoSuit, -1, -1
+	for _, suit := range card.Suits {
+		trump := cards.AsTrump(suit).TrumpCards(suit)
+		points := 0
+		for _, c := range trump {
+			points += c.Points(suit)
+		}
+		if len(trump) > bestLen || len(trump) == bestLen && points > bestPoints {
+			best, bestLen, bestPoints = suit, len(trump), points
+		}
+	}
+	return best
+}
+
+// offered returns true iff the given bid is one of the Options of m, or m
+// is not a Bid Message, whose Options are not bids at all.
+func offered(m action.Message, b bid.Bid) bool {
+	if m.Type != action.Bid {
+		return true
+	}
+	for _, o := range m.Options {
+		if o == int(b) {
+			return true
+		}
 	}
+	return false
 }
 
 // drwSixBid implements a basic version of the logic drw uses
@@ -139,7 +139,9 @@ var fromBiddersTests = []struct {
 			},
 		},
 		action.Message{
-			Seat: seat.North,
+			Type:    action.Bid,
+			Seat:    seat.North,
+			Options: action.SelectionRange(0, len(bid.Values)),
 		},
 		[]int{int(bid.B1530)},
 	},
@@ -157,17 +159,94 @@ var fromBiddersTests = []struct {
 			},
 		},
 		action.Message{
-			Seat: seat.North,
+			Type:    action.Bid,
+			Seat:    seat.North,
+			Options: action.SelectionRange(0, len(bid.Values)),
 		},
 		[]int{int(bid.B6)},
 	},
+	{
+		"Six Not Allowed",
+		func(cards card.Set) (min, max bid.Bid) {
+			return bid.B6, bid.B7
+		},
+		func(cards card.Set) bool {
+			return true
+		},
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.North: &hand.Hand{card.Card{card.Ace, card.Spades}},
+			},
+		},
+		action.Message{
+			Type:    action.Bid,
+			Seat:    seat.North,
+			Options: []int{int(bid.Pass), int(bid.B7), int(bid.B8)},
+		},
+		[]int{int(bid.B7)},
+	},
+	{
+		"Stuck Dealer",
+		func(cards card.Set) (min, max bid.Bid) {
+			return bid.Pass, bid.Pass
+		},
+		func(cards card.Set) bool {
+			return false
+		},
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.North: &hand.Hand{card.Card{card.Ace, card.Spades}},
+			},
+		},
+		action.Message{
+			Type:    action.Bid,
+			Seat:    seat.North,
+			Options: []int{int(bid.B7), int(bid.B8)},
+		},
+		[]int{int(bid.B7)},
+	},
+	{
+		"Trump",
+		func(cards card.Set) (min, max bid.Bid) {
+			return bid.B9, bid.B9
+		},
+		func(cards card.Set) bool {
+			return false
+		},
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.North: &hand.Hand{card.Card{card.Ace, card.Spades}},
+			},
+			Bids: map[seat.Seat]bid.Bid{seat.North: bid.B7},
+		},
+		action.Message{
+			Type:    action.Trump,
+			Seat:    seat.North,
+			Options: action.SelectionRange(0, len(card.Suits)),
+		},
+		[]int{int(bid.B9)},
+	},
 }
 
 func TestFromBidders(t *testing.T) {
 	for _, test := range fromBiddersTests {
 		f := fromBidders(test.forSuit, test.isSix)
 		if got, _ := f(test.state, test.message); got[0] != test.want[0] {
-			t.Errorf("%s: want %s, got %s", test.name, test.want[0], got[0])
+			t.Errorf("%s: want %s, got %s", test.name, bid.Bid(test.want[0]), bid.Bid(got[0]))
 		}
 	}
 }
+
+func TestLongest(t *testing.T) {
+	cards := card.Set{
+		{card.Ace, card.Hearts},
+		{card.Five, card.Diamonds},
+		{card.Deuce, card.Clubs},
+		{card.Three, card.Clubs},
+	}
+	// Hearts and Clubs both hold two trump, but the off Five makes Hearts
+	// worth more.
+	if got := longest(cards); got != card.Hearts {
+		t.Errorf("want Hearts, got %s", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"dr2w.com/hf/model/card"
 	"dr2w.com/hf/model/seat"
 	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/model/trick"
 )
 
 // Logic wraps a state.State from a given Perspective and provides
@@ -15,6 +16,22 @@ type Logic struct {
 	Perspective seat.Seat
 }
 
+// current returns the Trick currently being played: the last Trick if it is
+// incomplete, otherwise a new Trick with every Seat which has thrown in Out.
+func (l Logic) current() trick.Trick {
+	last := l.State.LastPlayed()
+	if !last.Full() && !last.Empty() {
+		return last
+	}
+	t := trick.Trick{Cards: map[seat.Seat]card.Card{}, Out: map[seat.Seat]bool{}}
+	for st, folded := range l.State.Folded {
+		if folded {
+			t.Out[st] = true
+		}
+	}
+	return t
+}
+
 // played returns all cards played on *previous* tricks.
 func (l Logic) played() card.Set {
 	s := card.Set{}
@@ -33,18 +50,53 @@ func (l Logic) TrumpPlayed() card.Set {
 	return l.played().TrumpCards(l.State.Trump)
 }
 
+// TrumpDiscarded returns all trump publicly discarded this round.
+func (l Logic) TrumpDiscarded() card.Set {
+	s := card.Set{}
+	for _, r := range l.State.Revealed {
+		if r.Discarded {
+			s = append(s, r.Card)
+		}
+	}
+	return s.AsTrump(l.State.Trump).TrumpCards(l.State.Trump)
+}
+
+// TrumpOut returns every trump which has been neither played on a previous
+// trick nor publicly discarded.
 func (l Logic) TrumpOut() card.Set {
 	out := card.Set{}
 	played := l.TrumpPlayed()
+	discarded := l.TrumpDiscarded()
 	for _, v := range card.Values {
 		c := card.Card{v, l.State.Trump}
-		if !played.Contains(c) {
+		if !played.Contains(c) && !discarded.Contains(c) {
 			out = append(out, c)
 		}
 	}
 	return out
 }
 
+// Known returns the cards publicly known to be held by the given Seat: those
+// revealed in its Hand which it has not yet played.
+func (l Logic) Known(st seat.Seat) card.Set {
+	known := card.Set{}
+	for _, r := range l.State.Revealed {
+		if r.Discarded || r.Seat != st {
+			continue
+		}
+		played := false
+		for _, t := range l.State.Played {
+			if c, ok := t.Cards[st]; ok && c == r.Card {
+				played = true
+			}
+		}
+		if !played {
+			known = append(known, r.Card)
+		}
+	}
+	return known
+}
+
 func (l Logic) MyTrump() card.Set {
 	return l.MyHand().AsTrump(l.State.Trump).TrumpCards(l.State.Trump)
 }
@@ -62,7 +114,7 @@ func (l Logic) IHaveHighCardOut() bool {
 }
 
 func (l Logic) IAmLeading() bool {
-	return l.State.LastPlayed().Empty()
+	return l.current().Empty()
 }
 
 func (l Logic) OffsuitLead() bool {
@@ -96,9 +148,10 @@ func (l Logic) AFiveIsOut() bool {
             l.TrumpOut().Contains(card.Card{card.OffFive, l.State.Trump})
 }
 
+// IAmLast returns true iff every other Seat has either played on the current
+// Trick or thrown in.
 func (l Logic) IAmLast() bool {
-	// TODO(drw): Add support for players throwing in.
-	return len(l.State.LastPlayed().Cards) == 3
+	return l.current().Remaining() == 1
 }
 
 func (l Logic) IHaveHighCard() bool {
@@ -118,9 +171,10 @@ func (l Logic) PointsAreShowing() bool {
     return points > 0
 }
 
+// NextPlayerIsLast returns true iff exactly one Seat still to play on the
+// current Trick follows this one.
 func (l Logic) NextPlayerIsLast() bool {
-	// TODO(drw): Add support for players throwing in.
-    return len(l.State.LastPlayed().Cards) == 2
+    return l.current().Remaining() == 2
 }
 
 func (l Logic) TrickHasAFive() bool {
@@ -139,8 +193,9 @@ func (l Logic) PartnerPlayedHighCardOut() bool {
 }
 
 func (l Logic) PartnerToPlay() bool {
-    _, ok := l.State.LastPlayed().Cards[l.Perspective.Partner()]
-    return !ok
+    t := l.current()
+    _, ok := t.Cards[l.Perspective.Partner()]
+    return !ok && !t.Out[l.Perspective.Partner()]
 }
 
 func max(s card.Set) card.Card {
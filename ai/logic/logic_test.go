@@ -0,0 +1,123 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/model/trick"
+)
+
+var (
+	c7d = card.Card{card.Seven, card.Diamonds}
+	c9d = card.Card{card.Nine, card.Diamonds}
+	c3c = card.Card{card.Three, card.Clubs}
+	c9h = card.Card{card.Nine, card.Hearts}
+)
+
+var positionTests = []struct {
+	name          string
+	state         state.State
+	perspective   seat.Seat
+	leading       bool
+	last          bool
+	nextIsLast    bool
+	partnerToPlay bool
+}{
+	{
+		"Leading A New Trick",
+		state.State{Played: []trick.Trick{trick.New(c7d, c9d, c3c, c9h)}},
+		seat.East,
+		true,
+		false,
+		false,
+		true,
+	},
+	{
+		"Last Of Four",
+		state.State{Played: []trick.Trick{trick.New(c7d, c9d, c3c)}},
+		seat.West,
+		false,
+		true,
+		false,
+		false,
+	},
+	{
+		"Last Because Partner Threw In",
+		state.State{
+			Played: []trick.Trick{{
+				Cards: map[seat.Seat]card.Card{seat.North: c7d, seat.East: c9d},
+				First: seat.North,
+				Out:   map[seat.Seat]bool{seat.West: true},
+			}},
+			Folded: map[seat.Seat]bool{seat.West: true},
+		},
+		seat.South,
+		false,
+		true,
+		false,
+		false,
+	},
+	{
+		"Next Is Last Because Partner And Another Threw In",
+		state.State{
+			Played: []trick.Trick{trick.New(c7d, c9d, c3c, c9h)},
+			Folded: map[seat.Seat]bool{seat.North: true, seat.West: true},
+		},
+		seat.East,
+		true,
+		false,
+		true,
+		false,
+	},
+}
+
+func TestPosition(t *testing.T) {
+	for _, test := range positionTests {
+		l := Logic{test.state, test.perspective}
+		if got := l.IAmLeading(); got != test.leading {
+			t.Errorf("%s: IAmLeading() want %v, got %v", test.name, test.leading, got)
+		}
+		if got := l.IAmLast(); got != test.last {
+			t.Errorf("%s: IAmLast() want %v, got %v", test.name, test.last, got)
+		}
+		if got := l.NextPlayerIsLast(); got != test.nextIsLast {
+			t.Errorf("%s: NextPlayerIsLast() want %v, got %v", test.name, test.nextIsLast, got)
+		}
+		if got := l.PartnerToPlay(); got != test.partnerToPlay {
+			t.Errorf("%s: PartnerToPlay() want %v, got %v", test.name, test.partnerToPlay, got)
+		}
+	}
+}
+
+func TestRevealed(t *testing.T) {
+	s := state.State{
+		Trump: card.Hearts,
+		Played: []trick.Trick{{
+			Cards: map[seat.Seat]card.Card{seat.North: {card.Ace, card.Hearts}},
+			First: seat.North,
+		}},
+		Revealed: []state.Reveal{
+			{seat.East, card.Card{card.Three, card.Hearts}, true},
+			{seat.North, card.Card{card.Ace, card.Hearts}, false},
+			{seat.North, card.Card{card.King, card.Hearts}, false},
+		},
+	}
+	l := Logic{s, seat.South}
+	out := l.TrumpOut()
+	if out.Contains(card.Card{card.Three, card.Hearts}) {
+		t.Errorf("TrumpOut() contains a discarded trump: %v", out)
+	}
+	if !out.Contains(card.Card{card.King, card.Hearts}) {
+		t.Errorf("TrumpOut() is missing a held trump: %v", out)
+	}
+	want := card.Set{{card.King, card.Hearts}}
+	if got := l.Known(seat.North); !reflect.DeepEqual(got, want) {
+		t.Errorf("Known(North) want %v, got %v", want, got)
+	}
+	if got := l.Known(seat.East); len(got) != 0 {
+		t.Errorf("Known(East) want nothing, got %v", got)
+	}
+}
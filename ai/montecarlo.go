@@ -0,0 +1,222 @@
+package ai
+
+import (
+	"math/rand"
+	"time"
+
+	"dr2w.com/hf/ai/logic"
+	"dr2w.com/hf/ai/playing"
+	"dr2w.com/hf/ai/solver"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+// attempts bounds the number of times a single sample is redrawn when the
+// cards left in the pool cannot satisfy every Seat's constraints.
+const attempts = 20
+
+// MonteCarlo decides Play and ThrowIn Messages by sampling deals of the
+// cards it cannot see which are consistent with everything the table knows,
+// solving each sample double dummy, and choosing the option with the best
+// total over all the samples.
+type MonteCarlo struct {
+	// Samples is the maximum number of deals to solve per decision.
+	Samples int
+	// Budget bounds the time spent on a decision; at least one sample is
+	// always solved. Zero means no bound.
+	Budget time.Duration
+}
+
+// Play implements Decider.
+func (mc MonteCarlo) Play(s state.State, m action.Message) []int {
+	if len(m.Options) == 1 {
+		return []int{m.Options[0]}
+	}
+	deadline := time.Now().Add(mc.Budget)
+	totals := make([]int, len(m.Options))
+	solved := 0
+	for i := 0; i < mc.Samples; i++ {
+		if solved > 0 && mc.Budget > 0 && time.Now().After(deadline) {
+			break
+		}
+		d, ok := sample(s, m.Seat, s.Rng())
+		if !ok {
+			continue
+		}
+		values, err := solver.Evaluate(d, m)
+		if err != nil {
+			continue
+		}
+		for j, v := range values {
+			totals[j] += v
+		}
+		solved++
+	}
+	if solved == 0 {
+		if m.Type == action.Play {
+			return playing.InconsistentPlayer(s, m)
+		}
+		return last(s, m)
+	}
+	best := 0
+	for j, t := range totals {
+		if t > totals[best] {
+			best = j
+		}
+	}
+	return []int{m.Options[best]}
+}
+
+// constraints holds what the table knows about the unseen cards of a Seat.
+type constraints struct {
+	size  int
+	known card.Set
+	voids map[card.Suit]bool
+	// trump is the minimum number of trump the Seat must hold beyond those
+	// known.
+	trump int
+}
+
+// infer returns the constraints on each Seat other than me from the public
+// information in s: revealed cards, the trump kept going into the redeal,
+// suits not followed on previous Tricks and Seats which chose to play on
+// without trump.
+func infer(s state.State, me seat.Seat) map[seat.Seat]*constraints {
+	l := logic.Logic{State: s, Perspective: me}
+	cs := make(map[seat.Seat]*constraints)
+	for _, st := range seat.Order {
+		if st == me || s.Hands[st] == nil {
+			continue
+		}
+		c := &constraints{
+			size:  s.Hands[st].Length(),
+			known: l.Known(st),
+			voids: make(map[card.Suit]bool),
+		}
+		if folded, decided := s.Folded[st]; decided && !folded {
+			c.voids[s.Trump] = true
+		}
+		c.trump = s.Kept[st] - c.known.TrumpCards(s.Trump).Length()
+		cs[st] = c
+	}
+	for _, t := range s.Played {
+		lead := t.SuitLead()
+		for st, played := range t.Cards {
+			c, ok := cs[st]
+			if !ok || played == (card.Card{}) {
+				continue
+			}
+			if played.Suit == s.Trump {
+				c.trump--
+			}
+			if st != t.First && played.Suit != lead && (played.Suit != s.Trump || lead == s.Trump) {
+				c.voids[lead] = true
+			}
+		}
+	}
+	return cs
+}
+
+// unseen returns every card which me cannot place: those not in its own
+// Hand, not yet played, not discarded by me and not publicly discarded.
+func unseen(s state.State, me seat.Seat) card.Set {
+	seen := append(card.Set(*s.Hands[me]), s.Discards[me]...)
+	for _, t := range s.Played {
+		seen = append(seen, t.AsCardSet()...)
+	}
+	for _, r := range s.Revealed {
+		if r.Discarded {
+			seen = append(seen, r.Card)
+		}
+	}
+	seen = seen.AsTrump(s.Trump)
+	var cards card.Set
+	for _, c := range card.Set(deck.New()).AsTrump(s.Trump) {
+		if !seen.Contains(c) {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// sample returns a copy of s in which the Hands of every Seat but me are
+// filled with cards drawn at random from those me cannot see, consistently
+// with what the table knows. It returns false if no consistent deal was
+// found.
+func sample(s state.State, me seat.Seat, r *rand.Rand) (state.State, bool) {
+	cs := infer(s, me)
+	pool := unseen(s, me)
+	for _, c := range cs {
+		for _, k := range c.known {
+			for i, p := range pool {
+				if p == k {
+					pool = append(pool[:i:i], pool[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	for i := 0; i < attempts; i++ {
+		if hands, ok := draw(s.Trump, cs, pool, r); ok {
+			d := s
+			d.Hands = map[seat.Seat]*hand.Hand{me: s.Hands[me]}
+			for st, h := range hands {
+				h := h
+				d.Hands[st] = &h
+			}
+			return d, true
+		}
+	}
+	return state.State{}, false
+}
+
+// draw makes a single attempt at dealing the pool to satisfy the given
+// constraints, placing each Seat's minimum trump before the rest.
+func draw(trump card.Suit, cs map[seat.Seat]*constraints, pool card.Set, r *rand.Rand) (map[seat.Seat]hand.Hand, bool) {
+	left := make(card.Set, 0, len(pool))
+	for _, i := range r.Perm(len(pool)) {
+		left = append(left, pool[i])
+	}
+	hands := make(map[seat.Seat]hand.Hand)
+	var order []seat.Seat
+	for _, st := range seat.Order {
+		if c, ok := cs[st]; ok {
+			order = append(order, st)
+			hands[st] = append(hand.Hand(nil), c.known...)
+		}
+	}
+	take := func(st seat.Seat, n int, onlyTrump bool) bool {
+		c := cs[st]
+		for i := 0; i < len(left) && n > 0; {
+			p := left[i]
+			if c.voids[p.Suit] || onlyTrump && p.Suit != trump {
+				i++
+				continue
+			}
+			hands[st] = append(hands[st], p)
+			left = append(left[:i], left[i+1:]...)
+			n--
+		}
+		return n == 0
+	}
+	for _, st := range order {
+		c := cs[st]
+		need := c.trump
+		if room := c.size - len(hands[st]); need > room {
+			need = room
+		}
+		if need > 0 && !take(st, need, true) {
+			return nil, false
+		}
+	}
+	for _, st := range order {
+		if need := cs[st].size - len(hands[st]); need > 0 && !take(st, need, false) {
+			return nil, false
+		}
+	}
+	return hands, true
+}
@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"io"
+	"log"
+	"math/rand"
+	"testing"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+// plays calls visit with the full State and Message of every Play Message in
+// a completed game between four DRW players.
+func plays(t *testing.T, seed int64, visit func(s state.State, m action.Message)) {
+	g, err := game.NewSeeded(seed, seat.East, DRW, DRW, DRW, DRW)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	_, err = game.Walk(g.Record, func(s state.State, step game.Step) error {
+		if step.Message.Type == action.Play {
+			visit(s, step.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %s", err)
+	}
+}
+
+func TestInferIsSound(t *testing.T) {
+	plays(t, 0, func(s state.State, m action.Message) {
+		for st, c := range infer(s.View(m.Seat), m.Seat) {
+			h := card.Set(*s.Hands[st])
+			if h.Length() != c.size {
+				t.Errorf("%s: want size %d, got %d", st, h.Length(), c.size)
+			}
+			for _, k := range c.known {
+				if !h.Contains(k) {
+					t.Errorf("%s: known card %s not in hand %v", st, k, h)
+				}
+			}
+			for _, k := range h {
+				if c.voids[k.Suit] {
+					t.Errorf("%s: inferred void in %s but holds %s", st, k.Suit, k)
+				}
+			}
+			if n := h.TrumpCards(s.Trump).Length() - c.known.TrumpCards(s.Trump).Length(); n < c.trump {
+				t.Errorf("%s: inferred at least %d more trump, but holds %d in %v", st, c.trump, n, h)
+			}
+		}
+	})
+}
+
+func TestSampleIsConsistent(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	plays(t, 1, func(s state.State, m action.Message) {
+		view := s.View(m.Seat)
+		d, ok := sample(view, m.Seat, r)
+		if !ok {
+			t.Errorf("no sample found for %s in\n%s", m, s)
+			return
+		}
+		seen := card.Set{}
+		for _, tr := range s.Played {
+			seen = append(seen, tr.AsCardSet()...)
+		}
+		for st, h := range d.Hands {
+			if h.Length() != s.Hands[st].Length() {
+				t.Errorf("%s: sampled %d cards, want %d", st, h.Length(), s.Hands[st].Length())
+			}
+			for _, c := range *h {
+				if seen.Contains(c) {
+					t.Errorf("%s: sampled %s twice", st, c)
+				}
+				seen = append(seen, c)
+			}
+		}
+		if card.Set(*d.Hands[m.Seat]).Shorthand() != card.Set(*s.Hands[m.Seat]).Shorthand() {
+			t.Errorf("sample changed the hand of %s", m.Seat)
+		}
+	})
+}
+
+func TestSampleSkipsOwnDiscards(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	discarded := false
+	plays(t, 1, func(s state.State, m action.Message) {
+		own := s.Discards[m.Seat]
+		discarded = discarded || len(own) > 0
+		for i := 0; i < 5; i++ {
+			d, ok := sample(s.View(m.Seat), m.Seat, r)
+			if !ok {
+				continue
+			}
+			for st, h := range d.Hands {
+				for _, c := range *h {
+					if st != m.Seat && own.Contains(c) {
+						t.Errorf("%s: sampled %s, which %s discarded", st, c, m.Seat)
+					}
+				}
+			}
+		}
+	})
+	if !discarded {
+		t.Fatalf("no Seat discarded anything")
+	}
+}
+
+func TestMonteCarloPlay(t *testing.T) {
+	mc := MonteCarlo{Samples: 3}
+	plays(t, 2, func(s state.State, m action.Message) {
+		if s.Hands[m.Seat].Length() > 2 {
+			return
+		}
+		view := s.View(m.Seat)
+		view.Rand = rand.New(rand.NewSource(0))
+		got := mc.Play(view, m)
+		if len(got) != 1 || !contains(m.Options, got[0]) {
+			t.Errorf("played %v, want one of %v", got, m.Options)
+		}
+	})
+}
+
+func contains(options []int, o int) bool {
+	for _, p := range options {
+		if p == o {
+			return true
+		}
+	}
+	return false
+}
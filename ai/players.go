@@ -1,11 +1,23 @@
 package ai
 
 import (
+	"time"
+
 	"dr2w.com/hf/ai/bidding"
 	"dr2w.com/hf/ai/playing"
 	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
 )
 
+func init() {
+	player.Register(Dumb.Name, func(seat.Seat) player.Player { return Dumb })
+	player.Register(DRW.Name, func(seat.Seat) player.Player { return DRW })
+	player.Register("MonteCarlo", func(seat.Seat) player.Player {
+		return Carlo(MonteCarlo{Samples: 20, Budget: time.Second})
+	})
+}
+
 // Dumb chooses randomly or always chooses the same option.
 var Dumb = AIPlayer{
 	Name: "Dumb",
@@ -13,8 +25,10 @@ var Dumb = AIPlayer{
 		action.Deal:    first,
 		action.Bid:     second,
 		action.Trump:   rand1,
+		action.ReDeal:  first,
 		action.Discard: simpleDiscard,
 		action.Play:    first,
+		action.ThrowIn: first,
 	},
 }
 
@@ -25,7 +39,21 @@ var DRW = AIPlayer{
 		action.Deal:    first,
 		action.Bid:     Decider(bidding.DRWValue),
 		action.Trump:   Decider(bidding.DRWSuit),
+		action.ReDeal:  first,
 		action.Discard: simpleDiscard,
 		action.Play:    Decider(playing.InconsistentPlayer),
+		action.ThrowIn: last,
 	},
 }
+
+// Carlo plays like DRW, but decides each play (and whether to throw in) by
+// Monte Carlo sampling with the given configuration.
+func Carlo(mc MonteCarlo) AIPlayer {
+	deciders := make(map[action.Type]Decider)
+	for t, d := range DRW.Deciders {
+		deciders[t] = d
+	}
+	deciders[action.Play] = mc.Play
+	deciders[action.ThrowIn] = mc.Play
+	return AIPlayer{Name: "MonteCarlo", Deciders: deciders}
+}
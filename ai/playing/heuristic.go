@@ -4,7 +4,6 @@
 package playing
 
 import "log"
-import "math/rand"
 import "sort"
 
 import "dr2w.com/hf/ai/logic"
@@ -238,7 +237,7 @@ func inconsistently(rate float64, score scorer) decider {
 		}
 		sort.Sort(sort.Reverse(jointSort{scores, m.Options}))
 		i := 0
-		for rand.Float64() < rate {
+		for s.Rng().Float64() < rate {
 			i = (i + 1) % len(m.Options)
 		}
 		return []int{m.Options[i]}
@@ -259,7 +258,7 @@ func noisily(rate float64, score scorer) decider {
 		var scores []float64
 		for _, option := range m.Options {
 			c := (*s.Hands[m.Seat])[option]
-			adjustment := rand.Float64()*rate*2 - 1 // [-1,-1] -> [-1,1]
+			adjustment := s.Rng().Float64()*rate*2 - 1 // [-1,-1] -> [-1,1]
 			scores = append(scores, score(s, m, c)+adjustment)
 		}
 		sort.Sort(sort.Reverse(jointSort{scores, m.Options}))
@@ -94,7 +94,7 @@ var posnState = state.State{
 
 func TestInconsistently(t *testing.T) {
 	for _, test := range inconsistentlyTests {
-		rand.Seed(0)
+		posnState.Rand = rand.New(rand.NewSource(0))
 		d := inconsistently(test.rate, posnScore)
 		results := make([]int, posnReplicates)
 		for i := 0; i < posnReplicates; i++ {
@@ -212,7 +212,7 @@ var noisilyTests = []struct {
 
 func TestNoisily(t *testing.T) {
 	for _, test := range noisilyTests {
-		rand.Seed(0)
+		posnState.Rand = rand.New(rand.NewSource(0))
 		d := noisily(test.rate, posnScore)
 		results := make([]int, posnReplicates)
 		for i := 0; i < posnReplicates; i++ {
@@ -233,7 +233,7 @@ func makeTrick(first seat.Seat, cards []card.Card) trick.Trick {
 		t[st] = c
 		st = st.Next()
 	}
-	return trick.Trick{t, first}
+	return trick.Trick{Cards: t, First: first}
 }
 
 func makeHands(st seat.Seat, cards []card.Card) map[seat.Seat]*hand.Hand {
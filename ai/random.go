@@ -1,7 +1,7 @@
 package ai
 
 import (
-	"math/rand"
+	"sort"
 
 	"dr2w.com/hf/model/action"
 	"dr2w.com/hf/model/state"
@@ -13,17 +13,32 @@ func simpleDiscard(s state.State, m action.Message) (discards []int) {
 	hand := s.Hands[m.Seat]
 	winner, _ := s.WinningBid()
 	if m.Seat != winner {
-		return hand.Discards(s.Trump)
+		return lowestFirst(s, m)
 	}
-	n := hand.ExtraCards()
-	d := hand.Discards(s.Trump)
+	n := hand.ExtraCards(s.Rules().HandSize)
+	d := hand.Discards(s.Trump, s.Rules().HandSize)
 	if len(m.Options) < n || len(d) < n {
-		return []int{} // Triggers downstream error
+		// No random choice would be allowed; leave it to the rules.
+		return lowestFirst(s, m)
 	}
 	discards = make([]int, n)
-	perm := rand.Perm(n)
+	perm := s.Rng().Perm(n)
 	for i := 0; i < n; i++ {
 		discards[i] = d[perm[i]]
 	}
 	return discards
 }
+
+// lowestFirst chooses the Expect lowest valued options, so that a non-winner
+// holding too many trump discards its non-trump and then its lowest trump.
+func lowestFirst(s state.State, m action.Message) []int {
+	hand := s.Hands[m.Seat]
+	options := append([]int(nil), m.Options...)
+	sort.SliceStable(options, func(i, j int) bool {
+		return hand.Get(options[i]).TrumpValue(s.Trump) < hand.Get(options[j]).TrumpValue(s.Trump)
+	})
+	if m.Expect < len(options) {
+		options = options[:m.Expect]
+	}
+	return options
+}
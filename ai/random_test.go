@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+func TestSimpleDiscardTooManyTrump(t *testing.T) {
+	h := &hand.Hand{
+		card.Card{card.Ace, card.Hearts},
+		card.Card{card.Nine, card.Hearts},
+		card.Card{card.Three, card.Hearts},
+		card.Card{card.Ace, card.Clubs},
+		card.Card{card.Eight, card.Hearts},
+		card.Card{card.Seven, card.Hearts},
+		card.Card{card.Four, card.Hearts},
+		card.Card{card.Six, card.Hearts},
+		card.Card{card.Deuce, card.Hearts},
+	}
+	s := state.State{
+		Bids:  map[seat.Seat]bid.Bid{seat.South: bid.B8},
+		Trump: card.Hearts,
+		Hands: map[seat.Seat]*hand.Hand{seat.East: h},
+	}
+	m := action.Message{Type: action.Discard, Seat: seat.East, Options: h.Discards(s.Trump, s.Rules().HandSize), Expect: h.NumToDiscard(s.Trump, s.Rules().HandSize)}
+	// The club goes first, then the lowest trump which score no points.
+	if got, want := simpleDiscard(s, m), []int{3, 2, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("discarded %v, want %v", got, want)
+	}
+}
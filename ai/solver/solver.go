@@ -0,0 +1,265 @@
+// Package solver computes optimal play for the play phase of a round with
+// every Hand exposed ("double dummy"), by alpha-beta minimax over the plays
+// offered by the action package.
+package solver
+
+import (
+	"fmt"
+	"sort"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+// Solve takes a full (unredacted) State in the play phase and the Play or
+// ThrowIn Message pending on it, and returns the number of points each Team
+// can guarantee from the current Trick onward when all four Seats play
+// perfectly. Points already won on completed Tricks are not included; points
+// already played to the current Trick are.
+func Solve(s state.State, m action.Message) (map[seat.Team]int, error) {
+	sv := newSolver(s)
+	ns, err := sv.search(s, m, -1, sv.total+1)
+	if err != nil {
+		return nil, err
+	}
+	return sv.scores(ns), nil
+}
+
+// Evaluate takes a full State and the Play or ThrowIn Message pending on it
+// and returns, for each of m.Options in order, the number of points m.Seat's
+// partnership can guarantee from the current Trick onward if that option is
+// chosen and all four Seats play perfectly afterwards.
+func Evaluate(s state.State, m action.Message) ([]int, error) {
+	if m.Type != action.Play && m.Type != action.ThrowIn {
+		return nil, fmt.Errorf("unable to evaluate %s outside the play phase", m)
+	}
+	sv := newSolver(s)
+	var values []int
+	for _, o := range m.Options {
+		gain, child, next, err := sv.apply(s, m, o)
+		if err != nil {
+			return nil, err
+		}
+		ns, err := sv.search(child, next, -1, sv.total+1)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, sv.scores(gain + ns)[m.Seat.Team()])
+	}
+	return values, nil
+}
+
+// Loss returns the number of points m.Seat's partnership gives up, against
+// perfect play, by choosing the given option of m. A perfect choice loses 0.
+func Loss(s state.State, m action.Message, choice int) (int, error) {
+	values, err := Evaluate(s, m)
+	if err != nil {
+		return 0, err
+	}
+	best, chosen := 0, -1
+	for i, v := range values {
+		if v > best {
+			best = v
+		}
+		if m.Options[i] == choice {
+			chosen = v
+		}
+	}
+	if chosen < 0 {
+		return 0, fmt.Errorf("choice %d is not an option of %s", choice, m)
+	}
+	return best - chosen, nil
+}
+
+// bound describes how a value stored in the transposition table relates to
+// the true value of its position.
+type bound int
+
+const (
+	exact bound = iota
+	lower
+	upper
+)
+
+type entry struct {
+	value int
+	bound bound
+}
+
+// solver holds the state of a single search. Values are always the points
+// North-South will win from the current Trick onward.
+type solver struct {
+	trump card.Suit
+	// total is the number of points still to be won.
+	total int
+	table map[string]entry
+}
+
+func newSolver(s state.State) *solver {
+	sv := &solver{trump: s.Trump, table: make(map[string]entry)}
+	for _, h := range s.Hands {
+		for _, c := range *h {
+			sv.total += c.Points(s.Trump)
+		}
+	}
+	if t := s.LastPlayed(); !t.Full() {
+		sv.total += t.Points(s.Trump)
+	}
+	return sv
+}
+
+// scores converts the points won by North-South into points for each Team.
+func (sv *solver) scores(ns int) map[seat.Team]int {
+	return map[seat.Team]int{
+		seat.NorthSouth: ns,
+		seat.EastWest:   sv.total - ns,
+	}
+}
+
+// search returns the points North-South will win from the given position
+// under perfect play, searching within the window (alpha, beta).
+func (sv *solver) search(s state.State, m action.Message, alpha, beta int) (int, error) {
+	if m.Type != action.Play && m.Type != action.ThrowIn {
+		return 0, nil
+	}
+	key := sv.key(s, m)
+	if e, ok := sv.table[key]; ok {
+		if e.bound == exact ||
+			e.bound == lower && e.value >= beta ||
+			e.bound == upper && e.value <= alpha {
+			return e.value, nil
+		}
+	}
+	a, b := alpha, beta
+	maximize := m.Seat.Team() == seat.NorthSouth
+	best := sv.total + 1
+	if maximize {
+		best = -1
+	}
+	for _, o := range sv.order(s, m) {
+		gain, child, next, err := sv.apply(s, m, o)
+		if err != nil {
+			return 0, err
+		}
+		v, err := sv.search(child, next, a-gain, b-gain)
+		if err != nil {
+			return 0, err
+		}
+		v += gain
+		if maximize && v > best {
+			best = v
+			if best > a {
+				a = best
+			}
+		} else if !maximize && v < best {
+			best = v
+			if best < b {
+				b = best
+			}
+		}
+		if a >= b {
+			break
+		}
+	}
+	e := entry{best, exact}
+	if best <= alpha {
+		e.bound = upper
+	} else if best >= beta {
+		e.bound = lower
+	}
+	sv.table[key] = e
+	return best, nil
+}
+
+// order returns the options of m in the order they should be searched:
+// highest cards first, since these most often take the Trick and so cut
+// off the rest of the search soonest.
+func (sv *solver) order(s state.State, m action.Message) []int {
+	options := append([]int(nil), m.Options...)
+	if m.Type != action.Play {
+		return options
+	}
+	h := s.Hands[m.Seat]
+	sort.SliceStable(options, func(i, j int) bool {
+		return h.Get(options[i]).TrumpValue(sv.trump) > h.Get(options[j]).TrumpValue(sv.trump)
+	})
+	return options
+}
+
+// apply takes the given option of m on s, returning the points
+// North-South won on any Tricks the option completed along with the
+// resulting State and Message.
+func (sv *solver) apply(s state.State, m action.Message, option int) (int, state.State, action.Message, error) {
+	current := len(s.Played)
+	if last := s.LastPlayed(); !last.Full() && !last.Empty() {
+		current--
+	}
+	child, next, err := action.NextState(s, action.Message{
+		Type:    m.Type,
+		Seat:    m.Seat,
+		Options: []int{option},
+		Expect:  1,
+	})
+	if err != nil {
+		return 0, state.State{}, action.Message{}, err
+	}
+	gain := 0
+	for i := current; i < len(child.Played); i++ {
+		t := child.Played[i]
+		if !t.Full() {
+			continue
+		}
+		if winner, _ := t.Winner(sv.trump); winner.Team() == seat.NorthSouth {
+			gain += t.Points(sv.trump)
+		}
+	}
+	return gain, child, next, nil
+}
+
+// key returns a string identifying everything about the position which
+// affects its future: the cards left in each Hand, the current Trick, the
+// throw in decisions made and the pending request. Each card is encoded as
+// its Value and Suit, and each Hand is sorted so that the order of its cards
+// does not matter.
+func (sv *solver) key(s state.State, m action.Message) string {
+	b := make([]byte, 0, 64)
+	b = append(b, byte(m.Type), byte(m.Seat))
+	for _, st := range seat.Order {
+		d, ok := s.Folded[st]
+		b = append(b, '|', byte(st), flag(ok), flag(d))
+		if h, ok := s.Hands[st]; ok {
+			start := len(b)
+			for _, c := range *h {
+				b = append(b, byte(c.Value), byte(c.Suit))
+			}
+			sortPairs(b[start:])
+		}
+	}
+	if t := s.LastPlayed(); !t.Full() && !t.Empty() {
+		b = append(b, '|', byte(t.First))
+		for _, st := range seat.Order {
+			if c, ok := t.Cards[st]; ok {
+				b = append(b, byte(st), byte(c.Value), byte(c.Suit))
+			}
+		}
+	}
+	return string(b)
+}
+
+func flag(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sortPairs sorts a slice of two byte pairs in place.
+func sortPairs(b []byte) {
+	for i := 2; i < len(b); i += 2 {
+		for j := i; j > 0 && (b[j] < b[j-2] || b[j] == b[j-2] && b[j+1] < b[j-1]); j -= 2 {
+			b[j], b[j+1], b[j-2], b[j-1] = b[j-2], b[j-1], b[j], b[j+1]
+		}
+	}
+}
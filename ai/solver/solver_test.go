@@ -0,0 +1,128 @@
+package solver
+
+import (
+	"math/rand"
+	"testing"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+// deal returns a State ready for North to lead, with n cards from a
+// randomly ordered deck in each Hand and Hearts as trump.
+func deal(r *rand.Rand, n int) (state.State, action.Message) {
+	d := deck.ShuffledWith(r)
+	s := state.State{
+		Dealer: seat.West,
+		Bids:   map[seat.Seat]bid.Bid{seat.North: bid.B6},
+		Trump:  card.Hearts,
+		Hands:  map[seat.Seat]*hand.Hand{},
+	}
+	for i, st := range seat.Order {
+		h := hand.Hand(card.Set(d[i*n : (i+1)*n]).AsTrump(s.Trump))
+		s.Hands[st] = &h
+	}
+	return s, action.Message{
+		Type:    action.Play,
+		Seat:    seat.North,
+		Options: action.SelectionRange(0, n),
+		Expect:  1,
+	}
+}
+
+// minimax returns the points North-South win from the given position by an
+// exhaustive search with no pruning or transposition table.
+func minimax(t *testing.T, sv *solver, s state.State, m action.Message) int {
+	if m.Type != action.Play && m.Type != action.ThrowIn {
+		return 0
+	}
+	maximize := m.Seat.Team() == seat.NorthSouth
+	best := -1
+	if !maximize {
+		best = sv.total + 1
+	}
+	for _, o := range m.Options {
+		gain, child, next, err := sv.apply(s, m, o)
+		if err != nil {
+			t.Fatalf("apply(%s, %d): %s", m, o, err)
+		}
+		v := gain + minimax(t, sv, child, next)
+		if maximize && v > best || !maximize && v < best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestSolveMatchesMinimax(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 30; i++ {
+		s, m := deal(r, 3)
+		want := minimax(t, newSolver(s), s, m)
+		got, err := Solve(s, m)
+		if err != nil {
+			t.Fatalf("Deal %d: unexpected error: %s", i, err)
+		}
+		if got[seat.NorthSouth] != want {
+			t.Errorf("Deal %d: North-South want %d, got %v\n%s", i, want, got, s)
+		}
+		if got[seat.NorthSouth]+got[seat.EastWest] != newSolver(s).total {
+			t.Errorf("Deal %d: scores %v do not sum to the points in play", i, got)
+		}
+	}
+}
+
+func TestSolveLeavesStateUntouched(t *testing.T) {
+	s, m := deal(rand.New(rand.NewSource(1)), 4)
+	before := s.String()
+	if _, err := Solve(s, m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if after := s.String(); after != before {
+		t.Errorf("Solve modified its State:\n%s\nbecame\n%s", before, after)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 10; i++ {
+		s, m := deal(r, 3)
+		solved, err := Solve(s, m)
+		if err != nil {
+			t.Fatalf("Deal %d: unexpected error: %s", i, err)
+		}
+		values, err := Evaluate(s, m)
+		if err != nil {
+			t.Fatalf("Deal %d: unexpected error: %s", i, err)
+		}
+		best, bestOption := -1, 0
+		for j, v := range values {
+			if v > best {
+				best, bestOption = v, m.Options[j]
+			}
+		}
+		if best != solved[m.Seat.Team()] {
+			t.Errorf("Deal %d: best option is worth %d, Solve found %d", i, best, solved[m.Seat.Team()])
+		}
+		for j, v := range values {
+			loss, err := Loss(s, m, m.Options[j])
+			if err != nil {
+				t.Fatalf("Deal %d: unexpected error: %s", i, err)
+			}
+			if loss != best-v {
+				t.Errorf("Deal %d: Loss(%d) want %d, got %d", i, m.Options[j], best-v, loss)
+			}
+		}
+		if loss, _ := Loss(s, m, bestOption); loss != 0 {
+			t.Errorf("Deal %d: the best option lost %d", i, loss)
+		}
+	}
+	if _, err := Evaluate(state.State{}, action.Message{Type: action.Bid}); err == nil {
+		t.Errorf("Evaluate accepted a Bid Message")
+	}
+}
@@ -0,0 +1,439 @@
+// Package app serves games of High Five over HTTP. Tables are created,
+// joined and played through a JSON API, and their changes can be followed
+// as a stream of server-sent events:
+//
+//	GET  /lobby                       the Lobby: public Tables yet to start
+//	GET  /invite/{code}               the Status of the Private Table invited to
+//	GET  /tables                      list every public Table
+//	POST /tables                      create a Table from a TableConfig
+//	GET  /tables/{id}?token=&invite=  the Table's Status
+//	POST /tables/{id}/join            claim a Seat: {"Seat": "North", "Name": "...", "Invite": "..."}
+//	POST /tables/{id}/leave           give up a Seat: {"Token": "..."}
+//	POST /tables/{id}/bot             seat a bot: {"Token": "...", "Seat": "West", "Player": "DRW"}
+//	POST /tables/{id}/ready           {"Token": "...", "Ready": true}
+//	POST /tables/{id}/rules           {"Token": "...", "Rules": "house"}
+//	POST /tables/{id}/play            answer: {"Token": "...", "Selection": [0]}
+//	GET  /tables/{id}/events?token=   a "status" event for every change
+//	GET  /play/{id}?token=&invite=    the page on which the Table is played
+//
+// A token is returned on joining a Table; without one, a Table is seen from
+// outside, with every Hand hidden. A Table waits in the lobby until every
+// Seat is taken, by people or bots, and every person is ready. Private Tables
+// are only seen by those who know their invite code.
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/seat"
+)
+
+// Server hosts any number of Tables, which it keeps in a Store.
+type Server struct {
+	// Timeout is given to the Tables created without a Timeout of their
+	// own.
+	Timeout time.Duration
+
+	store  Store
+	mu     sync.Mutex
+	tables map[string]*Table
+}
+
+// NewServer returns a Server with no Tables which keeps the Tables it hosts
+// in store, or only in memory if store is nil.
+func NewServer(store Store) *Server {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Server{store: store, tables: make(map[string]*Table)}
+}
+
+// Resume hosts every unfinished Table kept in the Store, carrying on with
+// the games which had started.
+func (s *Server) Resume() error {
+	saved, err := s.store.Find(Query{Unfinished: true})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sv := range saved {
+		if s.tables[sv.ID] != nil {
+			continue
+		}
+		t, err := RestoreTable(sv, s.store)
+		if err != nil {
+			return fmt.Errorf("resuming table %s: %w", sv.ID, err)
+		}
+		s.tables[sv.ID] = t
+	}
+	return nil
+}
+
+// CreateTable adds a new Table for the game described by c.
+func (s *Server) CreateTable(c TableConfig) (*Table, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := token()[:8]
+	for s.tables[id] != nil {
+		id = token()[:8]
+	}
+	if c.Private && c.Invite == "" {
+		c.Invite = inviteCode()
+		for s.invitedLocked(c.Invite) != nil {
+			c.Invite = inviteCode()
+		}
+	} else if c.Private && s.invitedLocked(c.Invite) != nil {
+		return nil, fmt.Errorf("invite code %q is taken", c.Invite)
+	}
+	if c.Timeout == 0 {
+		c.Timeout = s.Timeout
+	}
+	t, err := NewTable(id, c, s.store)
+	if err != nil {
+		return nil, err
+	}
+	s.tables[id] = t
+	return t, nil
+}
+
+// Table returns the Table with the given ID, or nil if there is none.
+func (s *Server) Table(id string) *Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tables[id]
+}
+
+// Tables returns every Table, ordered by ID.
+func (s *Server) Tables() []*Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tables []*Table
+	for _, t := range s.tables {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].ID < tables[j].ID })
+	return tables
+}
+
+// Close closes every Table and stops hosting them, leaving their games in
+// the Store to be resumed.
+func (s *Server) Close() {
+	s.mu.Lock()
+	tables := s.tables
+	s.tables = make(map[string]*Table)
+	s.mu.Unlock()
+	for _, t := range tables {
+		t.Close()
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		s.index(w, r)
+	case path == "lobby" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Lobby())
+	case len(parts) == 2 && parts[0] == "invite" && r.Method == http.MethodGet:
+		s.invited(w, r, parts[1])
+	case path == "tables" && r.Method == http.MethodGet:
+		s.list(w, r)
+	case path == "tables" && r.Method == http.MethodPost:
+		s.create(w, r)
+	case len(parts) == 2 && parts[0] == "play" && r.Method == http.MethodGet:
+		s.withTable(w, parts[1], func(t *Table) { s.page(w, r, t) })
+	case len(parts) == 2 && parts[0] == "tables" && r.Method == http.MethodGet:
+		s.withTable(w, parts[1], func(t *Table) { s.status(w, r, t) })
+	case len(parts) == 3 && parts[0] == "tables" && parts[2] == "join" && r.Method == http.MethodPost:
+		s.withTable(w, parts[1], func(t *Table) { s.join(w, r, t) })
+	case len(parts) == 3 && parts[0] == "tables" && lobbyActions[parts[2]] != nil && r.Method == http.MethodPost:
+		s.withTable(w, parts[1], func(t *Table) { s.lobby(w, r, t, lobbyActions[parts[2]]) })
+	case len(parts) == 3 && parts[0] == "tables" && parts[2] == "play" && r.Method == http.MethodPost:
+		s.withTable(w, parts[1], func(t *Table) { s.play(w, r, t) })
+	case len(parts) == 3 && parts[0] == "tables" && parts[2] == "events" && r.Method == http.MethodGet:
+		s.withTable(w, parts[1], func(t *Table) { s.events(w, r, t) })
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// withTable calls f with the Table with the given ID, or responds that
+// there is no such Table.
+func (s *Server) withTable(w http.ResponseWriter, id string, f func(t *Table)) {
+	t := s.Table(id)
+	if t == nil {
+		http.Error(w, fmt.Sprintf("no table %q", id), http.StatusNotFound)
+		return
+	}
+	f(t)
+}
+
+// writeJSON responds with v encoded as JSON.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Writing response: %s", err)
+	}
+}
+
+// writeError responds with err and the status code which fits it.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrBadToken), errors.Is(err, ErrBadInvite):
+		code = http.StatusForbidden
+	case errors.Is(err, ErrSeatTaken), errors.Is(err, ErrNotAsked), errors.Is(err, ErrStarted):
+		code = http.StatusConflict
+	case action.Illegal(err):
+		code = http.StatusUnprocessableEntity
+	}
+	http.Error(w, err.Error(), code)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.public())
+}
+
+// public returns the Status of every Table which is not Private.
+func (s *Server) public() []Status {
+	statuses := []Status{}
+	for _, t := range s.Tables() {
+		if st, _, _ := t.Status(""); !st.Private {
+			statuses = append(statuses, st)
+		}
+	}
+	return statuses
+}
+
+func (s *Server) invited(w http.ResponseWriter, r *http.Request, code string) {
+	t := s.Invited(code)
+	if t == nil {
+		writeError(w, ErrBadInvite)
+		return
+	}
+	st, _, _ := t.Status("")
+	writeJSON(w, http.StatusOK, st)
+}
+
+// view returns the Status of the Table as seen by whoever made the request,
+// as long as they are admitted to it.
+func view(r *http.Request, t *Table) (Status, <-chan struct{}, error) {
+	q := r.URL.Query()
+	if !t.Admits(q.Get("token"), q.Get("invite")) {
+		if q.Get("token") != "" {
+			return Status{}, nil, ErrBadToken
+		}
+		return Status{}, nil, ErrBadInvite
+	}
+	return t.Status(q.Get("token"))
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var c TableConfig
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		writeError(w, fmt.Errorf("reading table config: %w", err))
+		return
+	}
+	// Whoever knows the Seed can work out every hidden card.
+	c.Seed = 0
+	t, err := s.CreateTable(c)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	st, _, _ := t.Status("")
+	writeJSON(w, http.StatusCreated, st)
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request, t *Table) {
+	st, _, err := view(r, t)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// JoinRequest claims a Seat at a Table. A Private Table must be given its
+// Invite code.
+type JoinRequest struct {
+	Seat   seat.Seat
+	Name   string
+	Invite string `json:",omitempty"`
+}
+
+// JoinResponse holds the token which identifies the person who joined,
+// along with the Table as they see it.
+type JoinResponse struct {
+	Token  string
+	Status Status
+}
+
+func (s *Server) join(w http.ResponseWriter, r *http.Request, t *Table) {
+	var req JoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("reading join request: %w", err))
+		return
+	}
+	tok, err := t.Join(req.Seat, req.Name, req.Invite)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	st, _, err := t.Status(tok)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, JoinResponse{tok, st})
+}
+
+// LobbyRequest changes a Table in the lobby on behalf of the holder of the
+// Token. Each action reads only the fields it needs.
+type LobbyRequest struct {
+	Token string
+	// Seat and Player are the Seat to seat a bot in and the kind of bot,
+	// or no kind to open the Seat again.
+	Seat   seat.Seat `json:",omitempty"`
+	Player string    `json:",omitempty"`
+	Ready  bool      `json:",omitempty"`
+	Rules  string    `json:",omitempty"`
+}
+
+// lobbyActions maps the path of each action on a Table in the lobby to the
+// method making it.
+var lobbyActions = map[string]func(t *Table, req LobbyRequest) error{
+	"leave": func(t *Table, req LobbyRequest) error { return t.Leave(req.Token) },
+	"bot":   func(t *Table, req LobbyRequest) error { return t.SetBot(req.Token, req.Seat, req.Player) },
+	"ready": func(t *Table, req LobbyRequest) error { return t.SetReady(req.Token, req.Ready) },
+	"rules": func(t *Table, req LobbyRequest) error { return t.SetRules(req.Token, req.Rules) },
+}
+
+func (s *Server) lobby(w http.ResponseWriter, r *http.Request, t *Table, f func(t *Table, req LobbyRequest) error) {
+	var req LobbyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("reading lobby request: %w", err))
+		return
+	}
+	if err := f(t, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	st, _, err := t.Status(req.Token)
+	if errors.Is(err, ErrBadToken) {
+		// Whoever left the Table sees it from outside.
+		st, _, err = t.Status("")
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// PlayRequest answers the Message a Table is waiting on.
+type PlayRequest struct {
+	Token     string
+	Selection []int
+}
+
+func (s *Server) play(w http.ResponseWriter, r *http.Request, t *Table) {
+	var req PlayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("reading play request: %w", err))
+		return
+	}
+	if err := t.Submit(req.Token, req.Selection); err != nil {
+		writeError(w, err)
+		return
+	}
+	st, _, err := t.Status(req.Token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, st)
+}
+
+// events streams a "status" event holding the Table's Status as JSON, first
+// as it stands and then after every change, until the game is over or the
+// client goes away.
+func (s *Server) events(w http.ResponseWriter, r *http.Request, t *Table) {
+	if _, _, err := view(r, t); err != nil {
+		writeError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	for {
+		st, changed, err := view(r, t)
+		if err != nil {
+			return
+		}
+		b, err := json.Marshal(st)
+		if err != nil {
+			log.Printf("Encoding status of table %s: %s", t.ID, err)
+			return
+		}
+		fmt.Fprintf(w, "event: status\ndata: %s\n\n", b)
+		flusher.Flush()
+		if st.Over {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`
+<html>
+  <head>
+    <title>High Five</title>
+  </head>
+  <body>
+    <h1>High Five</h1>
+    <table>
+      <tr><th>Table</th><th>Seats</th><th></th></tr>
+      {{range .}}
+      <tr>
+        <td><a href="/play/{{.ID}}">{{or .Name .ID}}</a></td>
+        <td>{{range $seat, $s := .Seats}}{{$seat}}: {{$s.Name}}{{if $s.Bot}} (bot){{else if $s.Ready}} (ready){{end}} {{end}}</td>
+        <td>{{if .Over}}over{{else if .Started}}playing{{else}}waiting{{end}}</td>
+      </tr>
+      {{else}}
+      <tr><td colspan="3">No tables yet.</td></tr>
+      {{end}}
+    </table>
+  </body>
+</html>
+`))
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, s.public()); err != nil {
+		log.Printf("Rendering index: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
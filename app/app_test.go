@@ -0,0 +1,160 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/model/seat"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+// post sends v as JSON and decodes the response into out, if it is given,
+// returning the status code.
+func post(t *testing.T, url string, v interface{}, out interface{}) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("POST %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("POST %s: decoding response: %s", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestPlayOverHTTP(t *testing.T) {
+	ts := httptest.NewServer(NewServer(nil))
+	defer ts.Close()
+
+	var created Status
+	c := TableConfig{Players: map[seat.Seat]string{seat.North: "DRW", seat.South: "DRW", seat.West: "DRW"}}
+	if code := post(t, ts.URL+"/tables", c, &created); code != http.StatusCreated {
+		t.Fatalf("create table: status %d", code)
+	}
+	if created.Started {
+		t.Errorf("table started with an open seat")
+	}
+	table := ts.URL + "/tables/" + created.ID
+	if code := post(t, table+"/join", JoinRequest{Seat: seat.North, Name: "me"}, nil); code != http.StatusConflict {
+		t.Errorf("joined a seat held by a bot: status %d", code)
+	}
+	var joined JoinResponse
+	if code := post(t, table+"/join", JoinRequest{Seat: seat.East, Name: "me"}, &joined); code != http.StatusOK {
+		t.Fatalf("join: status %d", code)
+	}
+	if joined.Status.Started || joined.Status.Seats[seat.East] != (SeatStatus{Name: "me"}) {
+		t.Errorf("joined %+v, want a waiting table with me in the East", joined.Status)
+	}
+	var ready Status
+	if code := post(t, table+"/ready", LobbyRequest{Token: joined.Token, Ready: true}, &ready); code != http.StatusOK || !ready.Started {
+		t.Fatalf("ready: status %d, %+v, want a started table", code, ready)
+	}
+	if code := post(t, table+"/play", PlayRequest{"nobody", []int{0}}, nil); code != http.StatusForbidden {
+		t.Errorf("played with a bad token: status %d", code)
+	}
+
+	resp, err := http.Get(table + "/events?token=" + joined.Token)
+	if err != nil {
+		t.Fatalf("GET events: %s", err)
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	var last Status
+	answered := 0
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		last = Status{}
+		if err := json.Unmarshal([]byte(data), &last); err != nil {
+			t.Fatalf("decoding event: %s", err)
+		}
+		if !last.Asked {
+			continue
+		}
+		if len(last.Labels) != len(last.Message.Options) {
+			t.Errorf("labels %q for the options of %s", last.Labels, last.Message)
+		}
+		if bad := len(last.Message.Options) + 1; answered == 0 {
+			// Answer badly once, which the table should refuse.
+			if code := post(t, table+"/play", PlayRequest{joined.Token, []int{bad}}, nil); code != http.StatusUnprocessableEntity {
+				t.Errorf("played an option not offered: status %d", code)
+			}
+		}
+		selection := ai.DRW.Play(*last.State, *last.Message)
+		if code := post(t, table+"/play", PlayRequest{joined.Token, selection}, nil); code != http.StatusAccepted {
+			t.Fatalf("play %v in answer to %s: status %d", selection, last.Message, code)
+		}
+		answered++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading events: %s", err)
+	}
+	if !last.Over || last.Error != "" {
+		t.Fatalf("events ended before the game was over: %+v", last)
+	}
+	if answered == 0 {
+		t.Errorf("never asked to play")
+	}
+	if code := post(t, table+"/play", PlayRequest{joined.Token, []int{0}}, nil); code != http.StatusConflict {
+		t.Errorf("played after the game was over: status %d", code)
+	}
+
+	var listed []Status
+	lr, err := http.Get(ts.URL + "/tables")
+	if err != nil {
+		t.Fatalf("GET tables: %s", err)
+	}
+	defer lr.Body.Close()
+	if err := json.NewDecoder(lr.Body).Decode(&listed); err != nil || len(listed) != 1 || !listed[0].Over {
+		t.Errorf("listed %+v (%v), want the one finished table", listed, err)
+	}
+}
+
+func TestClientSeedIgnored(t *testing.T) {
+	s := NewServer(nil)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	var created Status
+	if code := post(t, ts.URL+"/tables", TableConfig{Seed: 7}, &created); code != http.StatusCreated {
+		t.Fatalf("create table: status %d", code)
+	}
+	if seed := s.Table(created.ID).Config().Seed; seed == 7 || seed == 0 {
+		t.Errorf("table seeded with %d, want a seed of its own", seed)
+	}
+}
+
+func TestUnknownTable(t *testing.T) {
+	ts := httptest.NewServer(NewServer(nil))
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/tables/missing")
+	if err != nil {
+		t.Fatalf("GET: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown table: status %d", resp.StatusCode)
+	}
+	if code := post(t, ts.URL+"/tables", TableConfig{Rules: "nonsense"}, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown rules: status %d", code)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileStore is a Store which keeps each game as a JSON file of its own,
+// named after its ID, in a directory.
+type FileStore struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore returns a FileStore keeping games in dir, which is created if
+// it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+// path returns the file the game with the given ID is kept in.
+func (f *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid game id %q", id)
+	}
+	return filepath.Join(f.Dir, id+".json"), nil
+}
+
+// Save implements Store. The file is replaced in a single step, so that a
+// crash never leaves a game half written.
+func (f *FileStore) Save(s Saved) error {
+	path, err := f.path(s.ID)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding game %s: %w", s.ID, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp, err := os.CreateTemp(f.Dir, s.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load implements Store.
+func (f *FileStore) Load(id string) (Saved, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return Saved{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.read(path)
+}
+
+// read decodes the game kept in the given file.
+func (f *FileStore) read(path string) (Saved, error) {
+	var s Saved
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, ErrNotFound
+	} else if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Find implements Store by reading every game in the directory.
+func (f *FileStore) Find(q Query) ([]Saved, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(f.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var found []Saved
+	for _, path := range paths {
+		s, err := f.read(path)
+		if err != nil {
+			return nil, err
+		}
+		if q.Matches(s) {
+			found = append(found, s)
+		}
+	}
+	sortSaved(found)
+	return found, nil
+}
@@ -0,0 +1,282 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+// The errors a Table in the lobby returns when it is asked to do something
+// it cannot.
+var (
+	// ErrStarted means the game has started, so the Table can no longer be
+	// changed.
+	ErrStarted = errors.New("table has started")
+	// ErrBadInvite means the invite code does not admit anyone to the
+	// Private Table.
+	ErrBadInvite = errors.New("wrong invite code")
+)
+
+// SeatStatus describes whoever sits in a Seat: a bot of the named kind, or
+// the named person, who may be ready to start.
+type SeatStatus struct {
+	Name  string
+	Bot   bool `json:",omitempty"`
+	Ready bool `json:",omitempty"`
+}
+
+// bots returns the kinds of Player, as registered with the player package,
+// which may be seated as bots: all of them but Stdio, which would wait on the
+// Server's own input.
+func bots() []string {
+	var names []string
+	for _, name := range player.Names() {
+		if name != "Stdio" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// newBot returns a bot of the named kind for the Seat, or an error if there
+// is no such kind of bot.
+func newBot(kind string, st seat.Seat) (player.Player, error) {
+	for _, name := range bots() {
+		if name == kind {
+			return player.New(kind, st)
+		}
+	}
+	return nil, fmt.Errorf("no kind of bot named %q", kind)
+}
+
+// inviteCode returns a fresh code admitting people to a Private Table.
+func inviteCode() string {
+	return strings.ToUpper(token()[:6])
+}
+
+// Config returns the TableConfig of the Table, with the changes made to it
+// in the lobby.
+func (t *Table) Config() TableConfig {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.config
+	c.Players = make(map[seat.Seat]string, len(t.config.Players))
+	for st, name := range t.config.Players {
+		c.Players[st] = name
+	}
+	return c
+}
+
+// Admits returns true iff the holder of the token, or of the invite code if
+// there is no token, may see the Table.
+func (t *Table) Admits(tok, invite string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tok != "" {
+		_, err := t.seatOf(tok)
+		return err == nil
+	}
+	return !t.config.Private || invite == t.config.Invite
+}
+
+// seats describes whoever sits in each Seat. It must be called with t.mu
+// held.
+func (t *Table) seats() map[seat.Seat]SeatStatus {
+	seats := make(map[seat.Seat]SeatStatus)
+	for st, name := range t.config.Players {
+		seats[st] = SeatStatus{Name: name, Bot: true, Ready: true}
+	}
+	for st, p := range t.people {
+		seats[st] = SeatStatus{Name: p.name, Ready: p.ready}
+	}
+	return seats
+}
+
+// open returns an error unless the Seat may be taken. It must be called with
+// t.mu held.
+func (t *Table) open(st seat.Seat) error {
+	if t.started {
+		return ErrStarted
+	}
+	if st.Team() == seat.NoTeam {
+		return fmt.Errorf("%w: %s", ErrSeatTaken, st)
+	}
+	if _, ok := t.config.Players[st]; ok {
+		return fmt.Errorf("%w: %s", ErrSeatTaken, st)
+	}
+	if _, ok := t.people[st]; ok {
+		return fmt.Errorf("%w: %s", ErrSeatTaken, st)
+	}
+	return nil
+}
+
+// lobbySeat returns the Seat of the holder of the token, as long as the Table
+// is still in the lobby. It must be called with t.mu held.
+func (t *Table) lobbySeat(tok string) (seat.Seat, error) {
+	if t.started {
+		return seat.None, ErrStarted
+	}
+	st, err := t.seatOf(tok)
+	if err == nil && st == seat.None {
+		err = ErrBadToken
+	}
+	return st, err
+}
+
+// Join claims the given open Seat for the named person and returns the
+// token which identifies them from then on. A Private Table must be given
+// its invite code.
+func (t *Table) Join(st seat.Seat, name, invite string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.config.Private && invite != t.config.Invite {
+		return "", ErrBadInvite
+	}
+	if err := t.open(st); err != nil {
+		return "", err
+	}
+	tok := token()
+	t.people[st] = &person{name: name, token: tok, answers: make(chan []int, 1)}
+	t.notify()
+	t.save()
+	return tok, nil
+}
+
+// Leave gives up the Seat of the holder of the token, before the game
+// starts.
+func (t *Table) Leave(tok string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, err := t.lobbySeat(tok)
+	if err != nil {
+		return err
+	}
+	delete(t.people, st)
+	t.notify()
+	t.save()
+	return nil
+}
+
+// SetBot seats a bot of the named kind in the given open Seat, or if kind is
+// empty, opens the Seat held by a bot. Only the people at the Table may do
+// so, before the game starts, which it does if the Table is then ready.
+func (t *Table) SetBot(tok string, st seat.Seat, kind string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.lobbySeat(tok); err != nil {
+		return err
+	}
+	if kind == "" {
+		if _, ok := t.config.Players[st]; !ok {
+			return fmt.Errorf("no bot in the %s seat", st)
+		}
+		delete(t.config.Players, st)
+		t.notify()
+		t.save()
+		return nil
+	}
+	if err := t.open(st); err != nil {
+		return err
+	}
+	if _, err := newBot(kind, st); err != nil {
+		return err
+	}
+	t.config.Players[st] = kind
+	t.notify()
+	return t.startIfReady()
+}
+
+// SetReady records whether the holder of the token is ready to start. The
+// game starts once every Seat is taken and every person is ready.
+func (t *Table) SetReady(tok string, ready bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, err := t.lobbySeat(tok)
+	if err != nil {
+		return err
+	}
+	t.people[st].ready = ready
+	t.notify()
+	return t.startIfReady()
+}
+
+// SetRules changes the rules preset the game is to be played by. Since the
+// people at the Table agreed to other rules, none of them is ready any more.
+func (t *Table) SetRules(tok, preset string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.lobbySeat(tok); err != nil {
+		return err
+	}
+	r, err := rules.Preset(preset)
+	if err != nil {
+		return err
+	}
+	t.config.Rules, t.rules = preset, r
+	for _, p := range t.people {
+		p.ready = false
+	}
+	t.notify()
+	t.save()
+	return nil
+}
+
+// startIfReady starts the game if every Seat is taken and every person is
+// ready, and otherwise saves the Table as it waits. It must be called with
+// t.mu held.
+func (t *Table) startIfReady() error {
+	seats := t.seats()
+	ready := len(seats) == len(seat.Order)
+	for _, s := range seats {
+		ready = ready && s.Ready
+	}
+	if !ready {
+		t.save()
+		return nil
+	}
+	return t.start(nil)
+}
+
+// Lobby lists the public Tables yet to start, along with the choices which
+// may be made in setting one up.
+type Lobby struct {
+	Tables []Status
+	// Bots names every kind of bot which may be seated.
+	Bots []string
+	// Rules names every rules preset which may be played by.
+	Rules []string
+}
+
+// Lobby returns the Server's Lobby.
+func (s *Server) Lobby() Lobby {
+	l := Lobby{Tables: []Status{}, Bots: bots(), Rules: rules.Presets()}
+	for _, t := range s.Tables() {
+		st, _, _ := t.Status("")
+		if !st.Private && !st.Started {
+			l.Tables = append(l.Tables, st)
+		}
+	}
+	return l
+}
+
+// Invited returns the Private Table the invite code admits people to, or nil
+// if there is none.
+func (s *Server) Invited(code string) *Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.invitedLocked(code)
+}
+
+// invitedLocked is Invited for callers holding s.mu.
+func (s *Server) invitedLocked(code string) *Table {
+	for _, t := range s.tables {
+		if c := t.Config(); c.Private && c.Invite == code {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dr2w.com/hf/model/seat"
+)
+
+func TestLobby(t *testing.T) {
+	s := NewServer(nil)
+	public, err := s.CreateTable(TableConfig{Name: "public", Players: map[seat.Seat]string{seat.North: "DRW"}})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	private, err := s.CreateTable(TableConfig{Name: "private", Private: true})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	invite := private.Config().Invite
+	if invite == "" || s.Invited(invite) != private || s.Invited("nonsense") != nil {
+		t.Fatalf("invite code %q does not lead to the private table", invite)
+	}
+	if l := s.Lobby(); len(l.Tables) != 1 || l.Tables[0].ID != public.ID || len(l.Bots) == 0 || len(l.Rules) == 0 {
+		t.Errorf("lobby %+v, want only the public table", l)
+	}
+
+	if _, err := private.Join(seat.East, "ann", "wrong"); !errors.Is(err, ErrBadInvite) {
+		t.Errorf("joined a private table with the wrong invite: %v", err)
+	}
+	if private.Admits("", "") || !private.Admits("", invite) || !public.Admits("", "") {
+		t.Errorf("admitted the wrong people")
+	}
+	ann, err := private.Join(seat.East, "ann", invite)
+	if err != nil {
+		t.Fatalf("Join: %s", err)
+	}
+	if st, _, _ := private.Status(ann); st.Invite != invite {
+		t.Errorf("ann sees invite %q, want %q", st.Invite, invite)
+	}
+	if st, _, _ := private.Status(""); st.Invite != "" {
+		t.Errorf("outsiders see invite %q", st.Invite)
+	}
+	if _, err := private.Join(seat.East, "bob", invite); !errors.Is(err, ErrSeatTaken) {
+		t.Errorf("claimed a taken seat: %v", err)
+	}
+	bob, err := private.Join(seat.West, "bob", invite)
+	if err != nil {
+		t.Fatalf("Join: %s", err)
+	}
+	for _, test := range []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"BotOutsider", private.SetBot("", seat.North, "DRW"), true},
+		{"UnknownBot", private.SetBot(ann, seat.North, "Nobody"), true},
+		{"BotInTakenSeat", private.SetBot(ann, seat.West, "DRW"), true},
+		{"NorthBot", private.SetBot(ann, seat.North, "Dumb"), false},
+		{"ReplaceBot", private.SetBot(bob, seat.North, ""), false},
+		{"SouthBot", private.SetBot(bob, seat.South, "DRW"), false},
+		{"NorthAgain", private.SetBot(bob, seat.North, "DRW"), false},
+		{"AnnReady", private.SetReady(ann, true), false},
+		{"UnknownRules", private.SetRules(ann, "nonsense"), true},
+		{"Rules", private.SetRules(bob, "house"), false},
+		{"BobReady", private.SetReady(bob, true), false},
+	} {
+		if (test.err != nil) != test.wantErr {
+			t.Errorf("%s: %v", test.name, test.err)
+		}
+	}
+	st, _, _ := private.Status(bob)
+	if st.Started || st.Rules != "house" || st.Seats[seat.East] != (SeatStatus{Name: "ann"}) || st.Seats[seat.North] != (SeatStatus{"DRW", true, true}) {
+		t.Errorf("status %+v, want ann no longer ready after the rules changed", st)
+	}
+	if err := private.Leave(bob); err != nil {
+		t.Fatalf("Leave: %s", err)
+	}
+	if _, _, err := private.Status(bob); !errors.Is(err, ErrBadToken) {
+		t.Errorf("bob is still seated after leaving: %v", err)
+	}
+	if err := private.SetBot(ann, seat.West, "DRW"); err != nil {
+		t.Fatalf("SetBot: %s", err)
+	}
+	if err := private.SetReady(ann, true); err != nil {
+		t.Fatalf("SetReady: %s", err)
+	}
+	if st, _, _ := private.Status(ann); !st.Started {
+		t.Errorf("table did not start once everyone was ready: %+v", st)
+	}
+	if err := private.Leave(ann); !errors.Is(err, ErrStarted) {
+		t.Errorf("left a started table: %v", err)
+	}
+	if _, err := private.Join(seat.West, "cy", invite); !errors.Is(err, ErrStarted) {
+		t.Errorf("joined a started table: %v", err)
+	}
+}
+
+func TestPrivateOverHTTP(t *testing.T) {
+	s := NewServer(nil)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	var created Status
+	if code := post(t, ts.URL+"/tables", TableConfig{Private: true, Invite: "SECRET"}, &created); code != http.StatusCreated {
+		t.Fatalf("create table: status %d", code)
+	}
+	if _, err := s.CreateTable(TableConfig{Private: true, Invite: "SECRET"}); err == nil {
+		t.Errorf("created two tables with the same invite code")
+	}
+	table := ts.URL + "/tables/" + created.ID
+	for _, test := range []struct {
+		url  string
+		want int
+	}{
+		{table, http.StatusForbidden},
+		{table + "?invite=WRONG", http.StatusForbidden},
+		{table + "?invite=SECRET", http.StatusOK},
+		{ts.URL + "/invite/SECRET", http.StatusOK},
+		{ts.URL + "/invite/WRONG", http.StatusForbidden},
+		{ts.URL + "/lobby", http.StatusOK},
+	} {
+		resp, err := http.Get(test.url)
+		if err != nil {
+			t.Fatalf("GET %s: %s", test.url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("GET %s: status %d, want %d", test.url, resp.StatusCode, test.want)
+		}
+	}
+	if code := post(t, table+"/join", JoinRequest{Seat: seat.North, Name: "ann"}, nil); code != http.StatusForbidden {
+		t.Errorf("joined without the invite: status %d", code)
+	}
+	var joined JoinResponse
+	if code := post(t, table+"/join", JoinRequest{Seat: seat.North, Name: "ann", Invite: "SECRET"}, &joined); code != http.StatusOK {
+		t.Fatalf("join: status %d", code)
+	}
+	var st Status
+	if code := post(t, table+"/bot", LobbyRequest{Token: joined.Token, Seat: seat.East, Player: "DRW"}, &st); code != http.StatusOK || st.Seats[seat.East].Name != "DRW" {
+		t.Errorf("seat a bot: status %d, %+v", code, st)
+	}
+	if code := post(t, table+"/leave", LobbyRequest{Token: joined.Token}, &st); code != http.StatusOK || st.Seat != seat.None {
+		t.Errorf("leave: status %d, %+v", code, st)
+	}
+	if code := post(t, table+"/ready", LobbyRequest{Token: joined.Token, Ready: true}, nil); code != http.StatusForbidden {
+		t.Errorf("ready after leaving: status %d", code)
+	}
+}
+
+func TestStdioIsNotABot(t *testing.T) {
+	s := NewServer(nil)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	stdio := TableConfig{Players: map[seat.Seat]string{seat.North: "Stdio"}}
+	if code := post(t, ts.URL+"/tables", stdio, nil); code != http.StatusBadRequest {
+		t.Errorf("create a table with a Stdio bot: status %d", code)
+	}
+	var created Status
+	if code := post(t, ts.URL+"/tables", TableConfig{}, &created); code != http.StatusCreated {
+		t.Fatalf("create table: status %d", code)
+	}
+	table := ts.URL + "/tables/" + created.ID
+	var joined JoinResponse
+	if code := post(t, table+"/join", JoinRequest{Seat: seat.North, Name: "ann"}, &joined); code != http.StatusOK {
+		t.Fatalf("join: status %d", code)
+	}
+	if code := post(t, table+"/bot", LobbyRequest{Token: joined.Token, Seat: seat.East, Player: "Stdio"}, nil); code != http.StatusBadRequest {
+		t.Errorf("seat a Stdio bot: status %d", code)
+	}
+	if st, _, _ := s.Table(created.ID).Status(""); len(st.Seats) != 1 {
+		t.Errorf("seats %+v, want only ann's", st.Seats)
+	}
+}
@@ -0,0 +1,457 @@
+package app
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/state"
+)
+
+// labels names each of the Options of the Message as the Stdio player shows
+// them, given the State as the Seat it is addressed to sees it.
+func labels(s state.State, m action.Message) []string {
+	if len(m.Options) == 0 {
+		return nil
+	}
+	names := make([]string, len(m.Options))
+	for i, o := range m.Options {
+		switch m.Type {
+		case action.Bid:
+			names[i] = bid.Bid(o).String()
+		case action.Trump:
+			if o >= 0 && o < len(card.Suits) {
+				names[i] = card.Suits[o].String()
+			}
+		case action.Discard, action.ReDeal, action.Play:
+			if h := s.Hands[m.Seat]; h != nil && o >= 0 && o < len(*h) {
+				names[i] = (*h)[o].Shorthand()
+			}
+		case action.ThrowIn:
+			names[i] = "Play on"
+			if o == action.Fold {
+				names[i] = "Throw in"
+			}
+		default:
+			names[i] = m.Type.String()
+		}
+	}
+	return names
+}
+
+// page serves the page on which a Table is joined and played in the browser.
+// The page finds the Table from its own path and reads the token and invite
+// code from its query, or the token from the browser's storage once it has
+// joined; it does everything else through the JSON API.
+func (s *Server) page(w http.ResponseWriter, r *http.Request, t *Table) {
+	w.Header().Set("Content-type", "text/html; charset=utf-8")
+	if _, err := io.WriteString(w, playPage); err != nil {
+		log.Printf("Writing play page for table %s: %s", t.ID, err)
+	}
+}
+
+const playPage = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>High Five</title>
+    <style>
+      body { font-family: sans-serif; margin: 1em auto; max-width: 48em; }
+      section { margin: 1em 0; }
+      h2 { font-size: 1.1em; margin: 0.5em 0; }
+      button { margin: 0.1em; }
+      .error { color: #b00; }
+      .seats td, .seats th, .sheet td, .sheet th { padding: 0.2em 0.6em; text-align: left; }
+      .trick { display: grid; grid-template-columns: repeat(3, 5em); grid-template-rows: repeat(3, 2.5em); text-align: center; }
+      .trick div { line-height: 2.5em; }
+      .suit { margin: 0.2em 0; }
+      .card { min-width: 3em; font-size: 1.1em; }
+      .red { color: #c00; }
+      .selected { background: #ffd54f; }
+    </style>
+  </head>
+  <body>
+    <h1 id="title">High Five</h1>
+    <p id="error" class="error"></p>
+    <section id="seats"></section>
+    <section id="scores"></section>
+    <section id="bidding"></section>
+    <section id="trick"></section>
+    <section id="prompt"></section>
+    <section id="hand"></section>
+    <section id="sheet"></section>
+    <script>
+"use strict";
+
+var id = location.pathname.split("/").filter(Boolean).pop();
+var params = new URLSearchParams(location.search);
+var token = params.get("token") || localStorage.getItem("hf-token-" + id) || "";
+var invite = params.get("invite") || "";
+var order = ["North", "East", "South", "West"];
+var teams = ["North-South", "East-West"];
+var suitSymbols = {S: "♠", H: "♥", D: "♦", C: "♣", X: ""};
+var suitNames = {S: "Spades", H: "Hearts", D: "Diamonds", C: "Clubs", X: "No suit"};
+var valueNames = {A: "A", K: "K", Q: "Q", J: "J", j: "Joker", T: "10", f: "Off 5"};
+var lobby = {Bots: [], Rules: []};
+var current = null;
+var selected = [];
+var events = null;
+
+// el returns a new element with the given attributes and children, which
+// may be strings; text is never parsed as HTML.
+function el(tag, attrs) {
+  var e = document.createElement(tag);
+  Object.keys(attrs || {}).forEach(function (k) {
+    if (k === "onclick") {
+      e.onclick = attrs[k];
+    } else if (attrs[k] !== false && attrs[k] !== undefined) {
+      e.setAttribute(k, attrs[k]);
+    }
+  });
+  for (var i = 2; i < arguments.length; i++) {
+    var c = arguments[i];
+    if (c !== null && c !== undefined) {
+      e.appendChild(typeof c === "string" ? document.createTextNode(c) : c);
+    }
+  }
+  return e;
+}
+
+function fill(sectionID) {
+  var section = document.getElementById(sectionID);
+  section.textContent = "";
+  for (var i = 1; i < arguments.length; i++) {
+    if (arguments[i]) {
+      section.appendChild(arguments[i]);
+    }
+  }
+}
+
+function showError(err) {
+  document.getElementById("error").textContent = err ? String(err.message || err) : "";
+}
+
+// cardName renders a card from its shorthand, such as "TH" or "jC".
+function cardName(c) {
+  var v = c.charAt(0), s = c.charAt(1);
+  if (c === "XX") {
+    return "?";
+  }
+  if (v === "j") {
+    return valueNames[v];
+  }
+  return (valueNames[v] || v) + suitSymbols[s];
+}
+
+function cardClass(c) {
+  var s = c.charAt(1);
+  return s === "H" || s === "D" ? "card red" : "card";
+}
+
+function query() {
+  var q = new URLSearchParams();
+  if (token) {
+    q.set("token", token);
+  }
+  if (invite) {
+    q.set("invite", invite);
+  }
+  return q.toString();
+}
+
+function post(action, body) {
+  showError(null);
+  return fetch("/tables/" + id + "/" + action, {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(body)
+  }).then(function (resp) {
+    if (!resp.ok) {
+      return resp.text().then(function (text) { throw new Error(text.trim()); });
+    }
+    return resp.json();
+  }).catch(showError);
+}
+
+function setToken(tok) {
+  token = tok;
+  if (tok) {
+    localStorage.setItem("hf-token-" + id, tok);
+  } else {
+    localStorage.removeItem("hf-token-" + id);
+  }
+  connect();
+}
+
+function join(seat) {
+  var name = prompt("Your name?");
+  if (!name) {
+    return;
+  }
+  post("join", {Seat: seat, Name: name, Invite: invite}).then(function (resp) {
+    if (resp) {
+      setToken(resp.Token);
+    }
+  });
+}
+
+function answer(selection) {
+  selected = [];
+  post("play", {Token: token, Selection: selection});
+}
+
+function renderSeats(st) {
+  var rows = order.map(function (seat) {
+    var s = st.Seats && st.Seats[seat];
+    var who = s ? s.Name + (s.Bot ? " (bot)" : s.Ready ? " (ready)" : "") : "open";
+    var actions = el("td");
+    if (!st.Started && !s && !token) {
+      actions.appendChild(el("button", {onclick: function () { join(seat); }}, "Sit here"));
+    }
+    if (!st.Started && token && !s) {
+      var pick = el("select");
+      lobby.Bots.forEach(function (b) { pick.appendChild(el("option", {value: b}, b)); });
+      actions.appendChild(pick);
+      actions.appendChild(el("button", {onclick: function () {
+        post("bot", {Token: token, Seat: seat, Player: pick.value});
+      }}, "Add bot"));
+    }
+    if (!st.Started && token && s && s.Bot) {
+      actions.appendChild(el("button", {onclick: function () {
+        post("bot", {Token: token, Seat: seat});
+      }}, "Remove bot"));
+    }
+    if (!st.Started && seat === st.Seat) {
+      actions.appendChild(el("button", {onclick: function () {
+        post("ready", {Token: token, Ready: !s.Ready});
+      }}, s.Ready ? "Not ready" : "Ready"));
+      actions.appendChild(el("button", {onclick: function () {
+        post("leave", {Token: token}).then(function (resp) {
+          if (resp) {
+            setToken("");
+          }
+        });
+      }}, "Leave"));
+    }
+    return el("tr", {}, el("th", {}, seat + (seat === st.Seat ? " (you)" : "")), el("td", {}, who), actions);
+  });
+  var rules = el("p", {}, "Rules: " + st.Rules);
+  if (!st.Started && st.Seat !== "X") {
+    var pick = el("select");
+    lobby.Rules.forEach(function (r) {
+      pick.appendChild(el("option", {value: r, selected: r === st.Rules ? "selected" : false}, r));
+    });
+    pick.onchange = function () { post("rules", {Token: token, Rules: pick.value}); };
+    rules = el("p", {}, "Rules: ", pick);
+  }
+  var invited = st.Invite ? el("p", {}, "Invite code: " + st.Invite) : null;
+  var table = el("table", {"class": "seats"});
+  rows.forEach(function (r) { table.appendChild(r); });
+  fill("seats", el("h2", {}, "Seats"), table, rules, invited);
+}
+
+function renderScores(st) {
+  var s = st.State;
+  var line = teams.map(function (tm) { return tm + ": " + ((s.Score || {})[tm] || 0); }).join("   ");
+  fill("scores", el("h2", {}, "Score"), el("p", {}, line), st.Over ? el("p", {}, "The game is over.") : null);
+}
+
+function renderBidding(s) {
+  var row = el("tr");
+  var head = el("tr");
+  order.forEach(function (seat) {
+    head.appendChild(el("th", {}, seat.charAt(0)));
+    row.appendChild(el("td", {}, (s.Bids || {})[seat] || ""));
+  });
+  var trump = s.Trump && s.Trump !== "X" ? el("p", {}, "Trump: " + suitNames[s.Trump] + " " + suitSymbols[s.Trump]) : null;
+  fill("bidding", el("h2", {}, "Bids (dealer " + s.Dealer + ")"), el("table", {"class": "seats"}, head, row), trump);
+}
+
+// renderTrick lays out the trick in play, or the last one taken, with each
+// card where its Seat sits.
+function renderTrick(s) {
+  var played = s.Played || [];
+  if (played.length === 0) {
+    fill("trick");
+    return;
+  }
+  var trick = played[played.length - 1];
+  var cards = trick.Cards || {};
+  var place = {North: [1, 2], West: [2, 1], East: [2, 3], South: [3, 2]};
+  var grid = el("div", {"class": "trick"});
+  order.forEach(function (seat) {
+    var c = cards[seat];
+    var label = c ? cardName(c) : (trick.Out || {})[seat] ? "out" : "";
+    grid.appendChild(el("div", {
+      "class": c ? cardClass(c) : "card",
+      style: "grid-row: " + place[seat][0] + "; grid-column: " + place[seat][1]
+    }, seat.charAt(0) + ": " + label));
+  });
+  fill("trick", el("h2", {}, "Trick " + played.length + " (led by " + trick.First + ")"), grid);
+}
+
+// renderHand shows the Seat's hand a suit to a line, as the console player
+// does, with the cards offered by the Message as buttons.
+function renderHand(st) {
+  var s = st.State, m = st.Message;
+  var hand = (s.Hands || {})[st.Seat];
+  if (!hand) {
+    fill("hand");
+    return;
+  }
+  var offered = st.Asked && (m.Type === "Play" || m.Type === "Discard" || m.Type === "ReDeal") ? m.Options : [];
+  var lines = [el("h2", {}, "Your hand")];
+  var line = null, suit = null;
+  hand.forEach(function (c, i) {
+    if (c.charAt(1) !== suit) {
+      suit = c.charAt(1);
+      line = el("div", {"class": "suit"}, suitNames[suit] + ": ");
+      lines.push(line);
+    }
+    var pick = offered.indexOf(i) >= 0;
+    var cls = cardClass(c) + (selected.indexOf(i) >= 0 ? " selected" : "");
+    line.appendChild(el("button", {"class": cls, disabled: pick ? false : "disabled", onclick: function () {
+      if (m.Type === "Play") {
+        answer([i]);
+        return;
+      }
+      var at = selected.indexOf(i);
+      if (at >= 0) {
+        selected.splice(at, 1);
+      } else if (selected.length < m.Expect) {
+        selected.push(i);
+      }
+      render(current);
+    }}, cardName(c)));
+  });
+  fill.apply(null, ["hand"].concat(lines));
+}
+
+// renderPrompt offers the choices of the Message the game is waiting on,
+// other than cards to play, or asks whether to accept another seat's
+// takeback.
+function renderPrompt(st) {
+  var m = st.Message;
+  if (st.Takeback) {
+    fill("prompt", el("p", {}, st.Takeback + " asks to take back their last decision."),
+      el("button", {onclick: function () { answer([1]); }}, "Allow"),
+      el("button", {onclick: function () { answer([0]); }}, "Refuse"));
+    return;
+  }
+  if (!st.Asked) {
+    var waiting = m && m.Seat !== "X" && !st.Over ? "Waiting for " + m.Seat + " (" + m.Type + ")" : "";
+    fill("prompt", el("p", {}, waiting));
+    return;
+  }
+  var rejected = st.Rejected ? el("p", {"class": "error"}, st.Rejected + ", please try again.") : null;
+  var choices = el("div");
+  if (m.Type === "Play") {
+    choices.appendChild(el("p", {}, "Play a card."));
+  } else if (m.Type === "Discard" || m.Type === "ReDeal") {
+    choices.appendChild(el("p", {}, "Choose " + m.Expect + " cards to discard (" + selected.length + " chosen)."));
+    choices.appendChild(el("button", {disabled: selected.length === m.Expect ? false : "disabled", onclick: function () {
+      answer(selected.slice().sort(function (a, b) { return a - b; }));
+    }}, "Discard"));
+  } else {
+    var prompts = {Bid: "Bid:", Trump: "Choose trump:", ThrowIn: "You are out of trump:", Deal: "Your deal:"};
+    choices.appendChild(el("p", {}, prompts[m.Type] || m.Type));
+    m.Options.forEach(function (o, i) {
+      var label = st.Labels[i];
+      if (m.Type === "Trump") {
+        label = suitNames[label] + " " + suitSymbols[label];
+      }
+      choices.appendChild(el("button", {onclick: function () { answer([o]); }}, label));
+    });
+  }
+  if (st.Takebacks) {
+    choices.appendChild(el("button", {onclick: function () { answer([-1]); }}, "Take back"));
+  }
+  fill("prompt", rejected, choices);
+}
+
+function renderSheet(s) {
+  var sheet = s.Scoresheet || [];
+  if (sheet.length === 0) {
+    fill("sheet");
+    return;
+  }
+  var table = el("table", {"class": "sheet"},
+    el("tr", {}, el("th", {}, "Round"), el("th", {}, "Bid"), el("th", {}, "Result"), el("th", {}, teams[0]), el("th", {}, teams[1])));
+  sheet.forEach(function (r) {
+    var passed = r.Bidder === "X";
+    var result = passed ? "" : r.Made ? "made by " + r.Margin : "set by " + -r.Margin;
+    table.appendChild(el("tr", {},
+      el("td", {}, String(r.Round)),
+      el("td", {}, passed ? "passed out" : r.Bid + " " + suitSymbols[r.Trump] + " by " + r.Bidder),
+      el("td", {}, result),
+      el("td", {}, String((r.Delta || {})[teams[0]] || 0)),
+      el("td", {}, String((r.Delta || {})[teams[1]] || 0))));
+  });
+  fill("sheet", el("h2", {}, "Scoresheet"), table);
+}
+
+function render(st) {
+  current = st;
+  document.getElementById("title").textContent = "High Five: " + (st.Name || st.ID);
+  if (st.Error) {
+    showError(st.Error);
+  }
+  renderSeats(st);
+  if (!st.Started) {
+    ["scores", "bidding", "trick", "prompt", "hand", "sheet"].forEach(function (s) { fill(s); });
+    return;
+  }
+  if (!st.Asked) {
+    selected = [];
+  }
+  renderScores(st);
+  renderBidding(st.State);
+  renderTrick(st.State);
+  renderPrompt(st);
+  renderHand(st);
+  renderSheet(st.State);
+}
+
+// connect follows the Table's events, starting over whenever the token
+// changes.
+function connect() {
+  if (events) {
+    events.close();
+  }
+  events = new EventSource("/tables/" + id + "/events?" + query());
+  events.addEventListener("status", function (e) {
+    var st = JSON.parse(e.data);
+    render(st);
+    if (st.Over) {
+      events.close();
+    }
+  });
+  events.onerror = function () {
+    if (events.readyState !== EventSource.CLOSED) {
+      return;
+    }
+    if (token && current === null) {
+      // The token is not known here; look on as an outsider instead.
+      setToken("");
+    } else if (current === null) {
+      showError("This table is private: open it with its invite code.");
+    }
+  };
+}
+
+fetch("/lobby").then(function (resp) { return resp.json(); }).then(function (l) {
+  lobby = l;
+  lobby.Bots = lobby.Bots || [];
+  if (current) {
+    render(current);
+  }
+}).catch(showError);
+connect();
+    </script>
+  </body>
+</html>
+`
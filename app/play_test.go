@@ -0,0 +1,67 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+func TestLabels(t *testing.T) {
+	h := hand.Hand{{card.Ace, card.Spades}, {card.Ten, card.Hearts}, {card.Joker, card.Clubs}}
+	s := state.State{Hands: map[seat.Seat]*hand.Hand{seat.East: &h}}
+	for _, test := range []struct {
+		name string
+		m    action.Message
+		want []string
+	}{
+		{"Bid", action.Message{Type: action.Bid, Seat: seat.East, Options: []int{int(bid.Pass), int(bid.B7), int(bid.B1428)}}, []string{"Pass", "7", "14/28"}},
+		{"Trump", action.Message{Type: action.Trump, Seat: seat.East, Options: []int{0, 1, 2, 3}}, []string{"D", "C", "H", "S"}},
+		{"Play", action.Message{Type: action.Play, Seat: seat.East, Options: []int{0, 2}}, []string{"AS", "jC"}},
+		{"Discard", action.Message{Type: action.Discard, Seat: seat.East, Options: []int{1, 9}, Expect: 1}, []string{"TH", ""}},
+		{"ThrowIn", action.Message{Type: action.ThrowIn, Seat: seat.East, Options: []int{action.PlayOn, action.Fold}}, []string{"Play on", "Throw in"}},
+		{"Deal", action.Message{Type: action.Deal, Seat: seat.East, Options: []int{0}}, []string{"Deal"}},
+		{"Hidden", action.Message{Type: action.Play, Seat: seat.North}, nil},
+	} {
+		if got := labels(s, test.m); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: labels %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPlayPage(t *testing.T) {
+	s := NewServer(nil)
+	table, err := s.CreateTable(TableConfig{})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	for _, test := range []struct {
+		path string
+		want int
+	}{
+		{"/play/" + table.ID, http.StatusOK},
+		{"/play/missing", http.StatusNotFound},
+	} {
+		resp, err := http.Get(ts.URL + test.path)
+		if err != nil {
+			t.Fatalf("GET %s: %s", test.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("GET %s: status %d, want %d", test.path, resp.StatusCode, test.want)
+		}
+		if test.want == http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-type"), "text/html") {
+			t.Errorf("GET %s: content type %q", test.path, resp.Header.Get("Content-type"))
+		}
+	}
+}
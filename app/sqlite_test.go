@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package app
+
+// The pure-Go SQLite driver TestSQLStore runs against.
+import _ "modernc.org/sqlite"
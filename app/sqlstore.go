@@ -0,0 +1,137 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sqlSchema creates the tables an SQLStore keeps games in: each game is kept
+// whole as JSON, along with the columns it may be looked up by.
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS games (
+		id      TEXT PRIMARY KEY,
+		created INTEGER NOT NULL,
+		updated INTEGER NOT NULL,
+		over    INTEGER NOT NULL,
+		data    TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS game_players (
+		game_id TEXT NOT NULL,
+		name    TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS game_players_name ON game_players (name)`,
+}
+
+// SQLStore is a Store which keeps games in an SQL database, such as an
+// embedded SQLite one. It uses "?" placeholders, so the database's driver
+// must accept them.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore returns an SQLStore keeping games in db, creating its tables
+// if they do not exist.
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	for _, stmt := range sqlSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("creating schema: %w", err)
+		}
+	}
+	return &SQLStore{db}, nil
+}
+
+// Save implements Store.
+func (d *SQLStore) Save(s Saved) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding game %s: %w", s.ID, err)
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range []string{`DELETE FROM games WHERE id = ?`, `DELETE FROM game_players WHERE game_id = ?`} {
+		if _, err := tx.Exec(stmt, s.ID); err != nil {
+			return err
+		}
+	}
+	over := 0
+	if s.Over {
+		over = 1
+	}
+	if _, err := tx.Exec(`INSERT INTO games (id, created, updated, over, data) VALUES (?, ?, ?, ?, ?)`,
+		s.ID, s.Created.UnixNano(), s.Updated.UnixNano(), over, string(b)); err != nil {
+		return err
+	}
+	for _, name := range s.Players() {
+		if _, err := tx.Exec(`INSERT INTO game_players (game_id, name) VALUES (?, ?)`, s.ID, name); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Load implements Store.
+func (d *SQLStore) Load(id string) (Saved, error) {
+	var data string
+	err := d.db.QueryRow(`SELECT data FROM games WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Saved{}, ErrNotFound
+	} else if err != nil {
+		return Saved{}, err
+	}
+	var s Saved
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return Saved{}, fmt.Errorf("decoding game %s: %w", id, err)
+	}
+	return s, nil
+}
+
+// Find implements Store.
+func (d *SQLStore) Find(q Query) ([]Saved, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	if q.Player != "" {
+		where = append(where, `id IN (SELECT game_id FROM game_players WHERE name = ?)`)
+		args = append(args, q.Player)
+	}
+	if !q.From.IsZero() {
+		where = append(where, `created >= ?`)
+		args = append(args, q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		where = append(where, `created < ?`)
+		args = append(args, q.To.UnixNano())
+	}
+	if q.Unfinished {
+		where = append(where, `over = 0`)
+	}
+	stmt := `SELECT data FROM games`
+	if len(where) > 0 {
+		stmt += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	rows, err := d.db.Query(stmt+` ORDER BY created, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var found []Saved
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var s Saved
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			return nil, fmt.Errorf("decoding game: %w", err)
+		}
+		found = append(found, s)
+	}
+	return found, rows.Err()
+}
@@ -1,17 +1,149 @@
+package app
 
 import (
-	"google.golang.org/appengine/datastore"
-	"dr2w/hf/game"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/seat"
 )
 
-type Store struct {
+// ErrNotFound is returned by a Store asked for a game it does not hold.
+var ErrNotFound = errors.New("game not found")
+
+// Person is someone who has claimed a Seat at a Table.
+type Person struct {
+	Name string
+	// Token is the secret which identifies the Person to the Table.
+	Token string
+	Ready bool `json:",omitempty"`
+}
+
+// Saved is a Table as kept by a Store: how it was set up, who claimed its
+// Seats and the Record of its game as far as it got, from which the game
+// can be resumed.
+type Saved struct {
+	ID     string
+	Config TableConfig
+	People map[seat.Seat]Person
+	// Record is empty until the game starts.
+	Record  game.Record
+	Created time.Time
+	Updated time.Time
+	Started bool
+	Over    bool
+}
+
+// Players returns the names of everyone, and every kind of bot, sitting at
+// the Table.
+func (s Saved) Players() []string {
+	var names []string
+	for _, st := range seat.Order {
+		if name, ok := s.Config.Players[st]; ok {
+			names = append(names, name)
+		} else if p, ok := s.People[st]; ok {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Query selects Saved games from a Store. The zero Query selects them all.
+type Query struct {
+	// Player, if set, selects the games in which a person of that name, or
+	// a bot of that kind, sat.
+	Player string
+	// From and To, if set, select the games created at or after From and
+	// before To.
+	From, To time.Time
+	// Unfinished selects only the games which are not over.
+	Unfinished bool
+}
+
+// Matches returns true iff the Query selects the Saved game.
+func (q Query) Matches(s Saved) bool {
+	if q.Unfinished && s.Over {
+		return false
+	}
+	if !q.From.IsZero() && s.Created.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !s.Created.Before(q.To) {
+		return false
+	}
+	if q.Player == "" {
+		return true
+	}
+	for _, name := range s.Players() {
+		if name == q.Player {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps games, finished or not, so that they outlast the Server.
+type Store interface {
+	// Save adds the game to the Store, replacing any with the same ID.
+	Save(s Saved) error
+	// Load returns the game with the given ID, or ErrNotFound.
+	Load(id string) (Saved, error)
+	// Find returns every game the Query selects, oldest first.
+	Find(q Query) ([]Saved, error)
+}
+
+// sortSaved orders games oldest first, breaking ties by ID.
+func sortSaved(games []Saved) {
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].Created.Equal(games[j].Created) {
+			return games[i].Created.Before(games[j].Created)
+		}
+		return games[i].ID < games[j].ID
+	})
+}
+
+// MemoryStore is a Store which keeps games only as long as it lives.
+type MemoryStore struct {
+	mu    sync.Mutex
+	games map[string]Saved
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{games: make(map[string]Saved)}
+}
 
+// Save implements Store.
+func (m *MemoryStore) Save(s Saved) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games[s.ID] = s
+	return nil
 }
 
-func (s Store) WriteGame(g *game.Game) {
-	return
+// Load implements Store.
+func (m *MemoryStore) Load(id string) (Saved, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.games[id]
+	if !ok {
+		return Saved{}, ErrNotFound
+	}
+	return s, nil
 }
 
-func (s Store) ReadGames() []game.Game {
-	return []game.Game{}
+// Find implements Store.
+func (m *MemoryStore) Find(q Query) ([]Saved, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found []Saved
+	for _, s := range m.games {
+		if q.Matches(s) {
+			found = append(found, s)
+		}
+	}
+	sortSaved(found)
+	return found, nil
 }
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/model/seat"
+)
+
+// testStore checks that a Store keeps and finds games as the interface
+// promises.
+func testStore(t *testing.T, store Store) {
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	games := []Saved{
+		{ID: "a", Config: TableConfig{Players: map[seat.Seat]string{seat.North: "DRW"}}, People: map[seat.Seat]Person{seat.East: {Name: "ann", Token: "t1", Ready: true}}, Created: day},
+		{ID: "b", Config: TableConfig{Players: map[seat.Seat]string{seat.North: "Dumb"}}, People: map[seat.Seat]Person{seat.South: {Name: "bob", Token: "t2"}}, Created: day.Add(time.Hour), Over: true},
+		{ID: "c", People: map[seat.Seat]Person{seat.West: {Name: "ann", Token: "t3"}}, Created: day.Add(48 * time.Hour)},
+	}
+	for _, g := range games {
+		if err := store.Save(g); err != nil {
+			t.Fatalf("Save %s: %s", g.ID, err)
+		}
+	}
+	games[0].Over = true
+	if err := store.Save(games[0]); err != nil {
+		t.Fatalf("Save %s again: %s", games[0].ID, err)
+	}
+	got, err := store.Load("a")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if !got.Over || got.People[seat.East] != (Person{Name: "ann", Token: "t1", Ready: true}) || !got.Created.Equal(day) {
+		t.Errorf("Load = %+v, want %+v", got, games[0])
+	}
+	if _, err := store.Load("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load of a missing game: %v, want ErrNotFound", err)
+	}
+	for _, test := range []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"All", Query{}, []string{"a", "b", "c"}},
+		{"Person", Query{Player: "ann"}, []string{"a", "c"}},
+		{"Bot", Query{Player: "Dumb"}, []string{"b"}},
+		{"Nobody", Query{Player: "zed"}, nil},
+		{"From", Query{From: day.Add(time.Hour)}, []string{"b", "c"}},
+		{"To", Query{To: day.Add(time.Hour)}, []string{"a"}},
+		{"Unfinished", Query{Unfinished: true}, []string{"c"}},
+	} {
+		found, err := store.Find(test.query)
+		if err != nil {
+			t.Errorf("%s: Find: %s", test.name, err)
+			continue
+		}
+		var ids []string
+		for _, s := range found {
+			ids = append(ids, s.ID)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%s: found %v, want %v", test.name, ids, test.want)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %s", err)
+	}
+	testStore(t, store)
+	if err := store.Save(Saved{ID: "../escape"}); err == nil {
+		t.Errorf("saved a game outside the directory")
+	}
+}
+
+// TestSQLStore runs against the SQLite driver built in with -tags sqlite, and
+// is skipped without one.
+func TestSQLStore(t *testing.T) {
+	var db *sql.DB
+	for _, driver := range sql.Drivers() {
+		if driver == "sqlite" || driver == "sqlite3" {
+			var err error
+			if db, err = sql.Open(driver, ":memory:"); err != nil {
+				t.Fatalf("Open: %s", err)
+			}
+			defer db.Close()
+			// Each connection to ":memory:" opens a database of its own.
+			db.SetMaxOpenConns(1)
+			break
+		}
+	}
+	if db == nil {
+		t.Skip("no SQLite driver built in; test with -tags sqlite")
+	}
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatalf("NewSQLStore: %s", err)
+	}
+	testStore(t, store)
+}
+
+// drive plays the Seat of the holder of the token as DRW would, making the
+// given number of choices and then waiting to be asked again, or playing
+// until the game is over if moves is negative. It returns the last Status
+// seen.
+func drive(t *testing.T, table *Table, tok string, moves int) Status {
+	for {
+		st, changed, err := table.Status(tok)
+		if err != nil {
+			t.Fatalf("Status: %s", err)
+		}
+		if st.Over || moves == 0 && st.Asked {
+			return st
+		}
+		if !st.Asked {
+			select {
+			case <-changed:
+			case <-time.After(10 * time.Second):
+				t.Fatalf("table %s stuck at\n%+v", table.ID, st)
+			}
+			continue
+		}
+		if err := table.Submit(tok, ai.DRW.Play(*st.State, *st.Message)); err != nil {
+			t.Fatalf("Submit: %s", err)
+		}
+		moves--
+	}
+}
+
+func TestResume(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %s", err)
+	}
+	first := NewServer(store)
+	c := TableConfig{Players: map[seat.Seat]string{seat.North: "DRW", seat.South: "DRW", seat.West: "DRW"}, Rules: "house", Seed: 11}
+	waiting, err := first.CreateTable(TableConfig{Players: map[seat.Seat]string{seat.North: "DRW"}})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	playing, err := first.CreateTable(c)
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	tok, err := playing.Join(seat.East, "ann", "")
+	if err != nil {
+		t.Fatalf("Join: %s", err)
+	}
+	if err := playing.SetReady(tok, true); err != nil {
+		t.Fatalf("SetReady: %s", err)
+	}
+	before := drive(t, playing, tok, 20)
+
+	// Restart, as if the first Server had gone away.
+	second := NewServer(store)
+	if err := second.Resume(); err != nil {
+		t.Fatalf("Resume: %s", err)
+	}
+	if second.Table(waiting.ID) == nil {
+		t.Errorf("table %s waiting for players was not resumed", waiting.ID)
+	}
+	resumed := second.Table(playing.ID)
+	if resumed == nil {
+		t.Fatalf("table %s was not resumed", playing.ID)
+	}
+	st := drive(t, resumed, tok, 0)
+	if !st.Started || st.State.Rounds != before.State.Rounds || !reflect.DeepEqual(st.State.Score, before.State.Score) {
+		t.Errorf("resumed at %+v, want %+v", st.State, before.State)
+	}
+	if st = drive(t, resumed, tok, -1); st.Error != "" {
+		t.Fatalf("resumed game failed: %s", st.Error)
+	}
+	saved, err := store.Find(Query{Player: "ann"})
+	if err != nil || len(saved) != 1 || !saved[0].Over || len(saved[0].Record.Score) == 0 {
+		t.Errorf("found %+v (%v), want ann's finished game", saved, err)
+	}
+	if unfinished, _ := store.Find(Query{Unfinished: true}); len(unfinished) != 1 || unfinished[0].ID != waiting.ID {
+		t.Errorf("unfinished games %+v, want only %s", unfinished, waiting.ID)
+	}
+}
@@ -0,0 +1,549 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/player"
+)
+
+// The errors a Table returns when it is asked to do something it cannot.
+var (
+	// ErrSeatTaken means the Seat is held by a bot or has been claimed.
+	ErrSeatTaken = errors.New("seat is not open")
+	// ErrBadToken means the token does not belong to any Seat at the Table.
+	ErrBadToken = errors.New("unknown token")
+	// ErrNotAsked means the game is not waiting on the Seat.
+	ErrNotAsked = errors.New("not your turn")
+)
+
+// The answers a person asked to let another Seat take back its last
+// decision may Submit.
+const (
+	RefuseTakeback = 0
+	AcceptTakeback = 1
+)
+
+// TableConfig describes a Table to create.
+type TableConfig struct {
+	// Name is shown in the lobby.
+	Name string
+	// Private Tables are left out of the lobby, and only admit people who
+	// know their Invite code, which is made up if it is left empty.
+	Private bool
+	Invite  string
+	// Players names the kind of bot, as registered with the player
+	// package, sitting in each Seat. Seats left out are open to people,
+	// and may be given to bots until the game starts.
+	Players map[seat.Seat]string
+	// Rules names the rules preset to play by; empty means rules.Standard.
+	Rules string
+	// First is the Seat which deals first; seat.None means seat.East.
+	First seat.Seat
+	// Seed determines every random choice of the game; zero means a seed
+	// drawn at random. Since it gives away every hidden card, it is never
+	// taken from clients.
+	Seed int64
+	// Takebacks lets a Seat take back its last decision, by answering with
+	// player.Takeback, if every other Seat accepts.
+	Takebacks bool
+	// Timeout is how long a person has to answer before the game makes
+	// their choice for them, or refuses the takeback they are asked to
+	// accept; zero means as long as they like.
+	Timeout time.Duration `json:",omitempty"`
+}
+
+// Status is a Table as seen from one of its Seats, or from outside the
+// Table if Seat is seat.None.
+type Status struct {
+	ID      string
+	Name    string
+	Private bool `json:",omitempty"`
+	// Invite is only shown to the people at a Private Table.
+	Invite string `json:",omitempty"`
+	Rules  string
+	Seat   seat.Seat
+	// Seats describes whoever sits in each Seat. Open Seats are left out.
+	Seats   map[seat.Seat]SeatStatus
+	Started bool
+	Over    bool
+	// Takebacks is true iff the Seats may take back their decisions.
+	Takebacks bool `json:",omitempty"`
+	// Version counts the changes made to the Table, so that a client can
+	// tell whether it has missed one.
+	Version int
+	// State is the game as the Seat sees it, once it has started.
+	State *state.State `json:",omitempty"`
+	// Message is the Message the game is waiting on. Its Options are only
+	// given to the Seat it is addressed to.
+	Message *action.Message `json:",omitempty"`
+	// Labels names each of the Message's Options, for clients which do not
+	// decode them: a bid, a suit, a card in the Seat's Hand or a decision.
+	Labels []string `json:",omitempty"`
+	// Asked is true iff the game is waiting on the Seat's answer to
+	// Message.
+	Asked bool `json:",omitempty"`
+	// Rejected explains why the Seat's last answer was not allowed.
+	Rejected string `json:",omitempty"`
+	// Takeback is the Seat asking the Seat to let it take back its last
+	// decision, to which the answer is AcceptTakeback or RefuseTakeback.
+	Takeback seat.Seat `json:",omitempty"`
+	// Error explains why the game stopped before it was over.
+	Error string `json:",omitempty"`
+}
+
+// person holds a Seat claimed by a person.
+type person struct {
+	name  string
+	token string
+	ready bool
+	// asked is the Message the game is waiting on the person to answer,
+	// or takeback the Seat waiting on them to accept its takeback, and
+	// answers carries their answer to either.
+	asked    *action.Message
+	takeback seat.Seat
+	answers  chan []int
+	rejected string
+}
+
+// Table is a single game hosted by the Server, played by any mix of people,
+// who play through the Server, and bots. It waits in the lobby until every
+// Seat is taken and every person is ready, and is saved to its Store
+// whenever it changes.
+type Table struct {
+	ID      string
+	store   Store
+	created time.Time
+	// ctx is cancelled when the Table is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// config is as created, with the changes made in the lobby.
+	config  TableConfig
+	rules   rules.Config
+	people  map[seat.Seat]*person
+	started bool
+	over    bool
+	err     error
+	version int
+	// state, message and record are the game as last observed; the Game
+	// itself is only touched by the goroutine playing it.
+	game    *game.Game
+	state   state.State
+	message action.Message
+	record  game.Record
+	// changed is closed, and replaced, whenever the Table changes.
+	changed chan struct{}
+	// done is closed once the game, if it started, has stopped.
+	done chan struct{}
+}
+
+// NewTable returns a Table for the game described by c, which starts at
+// once if c leaves no Seat open. The Table is saved to store, unless it is
+// nil.
+func NewTable(id string, c TableConfig, store Store) (*Table, error) {
+	t, err := newTable(id, c, store)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t, t.startIfReady()
+}
+
+// RestoreTable returns the Table which was saved, carrying on with its game
+// if it had started.
+func RestoreTable(s Saved, store Store) (*Table, error) {
+	t, err := newTable(s.ID, s.Config, store)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.created = s.Created
+	for st, p := range s.People {
+		t.people[st] = &person{name: p.Name, token: p.Token, ready: p.Ready, answers: make(chan []int, 1)}
+	}
+	if !s.Started {
+		return t, nil
+	}
+	return t, t.start(&s.Record)
+}
+
+// newTable returns a Table for the game described by c which has not
+// started.
+func newTable(id string, c TableConfig, store Store) (*Table, error) {
+	if c.Rules == "" {
+		c.Rules = rules.Standard.Name
+	}
+	r, err := rules.Preset(c.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if c.First == seat.None {
+		c.First = seat.East
+	}
+	for c.Seed == 0 {
+		c.Seed = seed()
+	}
+	if c.Private && c.Invite == "" {
+		c.Invite = inviteCode()
+	}
+	players := make(map[seat.Seat]string, len(c.Players))
+	for st, name := range c.Players {
+		players[st] = name
+	}
+	c.Players = players
+	t := &Table{
+		ID:      id,
+		config:  c,
+		rules:   r,
+		store:   store,
+		created: time.Now(),
+		people:  make(map[seat.Seat]*person),
+		changed: make(chan struct{}),
+	}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	for st, name := range c.Players {
+		if _, err := newBot(name, st); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// seed returns a secret seed for a game, which unlike the time cannot be
+// guessed.
+func seed() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("reading random seed: %s", err))
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// token returns a fresh secret identifying a person at a Table.
+func token() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("reading random token: %s", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// seatOf returns the Seat claimed with the given token, or seat.None if the
+// token is empty. It must be called with t.mu held.
+func (t *Table) seatOf(tok string) (seat.Seat, error) {
+	if tok == "" {
+		return seat.None, nil
+	}
+	for st, p := range t.people {
+		if p.token == tok {
+			return st, nil
+		}
+	}
+	return seat.None, ErrBadToken
+}
+
+// Status returns the Table as seen by the holder of the token, or from
+// outside the Table if the token is empty, along with a channel which is
+// closed when the Table next changes.
+func (t *Table) Status(tok string) (Status, <-chan struct{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, err := t.seatOf(tok)
+	if err != nil {
+		return Status{}, nil, err
+	}
+	s := Status{
+		ID:        t.ID,
+		Name:      t.config.Name,
+		Private:   t.config.Private,
+		Rules:     t.config.Rules,
+		Seat:      st,
+		Seats:     t.seats(),
+		Started:   t.started,
+		Over:      t.over,
+		Takebacks: t.config.Takebacks,
+		Version:   t.version,
+	}
+	if st != seat.None {
+		s.Invite = t.config.Invite
+	}
+	if t.err != nil {
+		s.Error = t.err.Error()
+	}
+	if !t.started {
+		return s, t.changed, nil
+	}
+	view := t.state.View(st)
+	s.State = &view
+	m := t.message
+	if m.Seat != st {
+		m.Options = nil
+	}
+	s.Message = &m
+	s.Labels = labels(view, m)
+	if p, ok := t.people[st]; ok {
+		s.Asked = p.asked != nil
+		s.Rejected = p.rejected
+		s.Takeback = p.takeback
+	}
+	return s, t.changed, nil
+}
+
+// Submit answers the Message the game is waiting on the holder of the token
+// to answer, or the takeback they are asked to accept. The selection is
+// checked against the Message, but whether the rules allow it is only known
+// once the game has tried it, when a rejection shows up in the Status.
+func (t *Table) Submit(tok string, selection []int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, err := t.seatOf(tok)
+	if err != nil {
+		return err
+	}
+	p, ok := t.people[st]
+	if ok && p.takeback != seat.None {
+		if len(selection) != 1 || selection[0] != AcceptTakeback && selection[0] != RefuseTakeback {
+			return fmt.Errorf("answer the takeback requested by %s with %d to accept or %d to refuse", p.takeback, AcceptTakeback, RefuseTakeback)
+		}
+		p.takeback = seat.None
+		p.answers <- []int{selection[0]}
+		t.notify()
+		return nil
+	}
+	if !ok || p.asked == nil {
+		return ErrNotAsked
+	}
+	takeback := len(selection) == 1 && selection[0] == player.Takeback
+	if !takeback {
+		if err := p.asked.Validate(selection); err != nil {
+			return err
+		}
+	}
+	p.asked = nil
+	p.rejected = ""
+	p.answers <- append([]int(nil), selection...)
+	t.notify()
+	return nil
+}
+
+// notify wakes everyone waiting for the Table to change. It must be called
+// with t.mu held.
+func (t *Table) notify() {
+	t.version++
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// save writes the Table to its Store, if it has one. It must be called with
+// t.mu held.
+func (t *Table) save() {
+	if t.store == nil {
+		return
+	}
+	s := Saved{
+		ID:      t.ID,
+		Config:  t.config,
+		People:  make(map[seat.Seat]Person),
+		Record:  t.record,
+		Created: t.created,
+		Updated: time.Now(),
+		Started: t.started,
+		Over:    t.over,
+	}
+	for st, p := range t.people {
+		s.People[st] = Person{p.name, p.token, p.ready}
+	}
+	if err := t.store.Save(s); err != nil {
+		log.Printf("Saving table %s: %s", t.ID, err)
+	}
+}
+
+// snapshot returns a copy of the Record of a game, which may go on being
+// added to while the copy is kept.
+func snapshot(r game.Record) game.Record {
+	r.Decks = append([]deck.Deck(nil), r.Decks...)
+	r.Steps = append([]game.Step(nil), r.Steps...)
+	if r.Score != nil {
+		score := make(map[seat.Team]int, len(r.Score))
+		for tm, sc := range r.Score {
+			score[tm] = sc
+		}
+		r.Score = score
+	}
+	return r
+}
+
+// start seats the Players and plays the game on a goroutine of its own,
+// resuming the recorded game if there is one. It must be called with t.mu
+// held.
+func (t *Table) start(record *game.Record) error {
+	var players []player.Player
+	for _, st := range seat.Order {
+		if name, ok := t.config.Players[st]; ok {
+			p, err := newBot(name, st)
+			if err != nil {
+				return err
+			}
+			players = append(players, p)
+		} else {
+			players = append(players, seated{t, st})
+		}
+	}
+	var (
+		g   *game.Game
+		err error
+	)
+	if record != nil && len(record.Steps) > 0 {
+		g, err = game.Resume(*record, players...)
+	} else {
+		g, err = game.NewWithRules(t.rules, t.config.Seed, t.config.First, players...)
+	}
+	if err != nil {
+		return err
+	}
+	g.Takebacks = t.config.Takebacks
+	// People are asked again however often they answer badly, and only
+	// forfeit their choice when they run out of time.
+	g.Reprompts = math.MaxInt
+	g.Forfeit = true
+	g.Observer = t.observe
+	t.started = true
+	t.game = g
+	t.state, t.message, t.record = g.State.Clone(), g.Message, snapshot(g.Record)
+	t.done = make(chan struct{})
+	t.notify()
+	t.save()
+	go func() {
+		defer close(t.done)
+		err := g.Resolve()
+		if errors.Is(err, game.ErrAbandoned) {
+			// The Table was closed, and the game is saved as it was.
+			return
+		}
+		if err != nil {
+			log.Printf("Table %s stopped: %s", t.ID, err)
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.over, t.err = true, err
+		t.record = snapshot(g.Record)
+		t.notify()
+		t.save()
+	}()
+	return nil
+}
+
+// Close stops the Table's game where it is, so that it may be resumed from
+// the Store, and waits for it to stop. Bots carry on until a person is
+// asked to play.
+func (t *Table) Close() {
+	t.cancel()
+	t.mu.Lock()
+	done := t.done
+	t.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// observe records the State and Message the game has moved on to. It is
+// called by the goroutine playing the game, which alone may read the Game.
+func (t *Table) observe(s state.State, m action.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state, t.message, t.record = s, m, snapshot(t.game.Record)
+	t.notify()
+	t.save()
+}
+
+// seated is the Player in a Seat claimed by a person, who answers through
+// the Table.
+type seated struct {
+	t  *Table
+	st seat.Seat
+}
+
+// Play implements player.Player by waiting for the person to Submit an
+// answer. Once the Table's Timeout passes the person forfeits their choice,
+// and once the Table is closed the game is abandoned.
+func (p seated) Play(s state.State, m action.Message) []int {
+	p.t.mu.Lock()
+	h := p.t.people[p.st]
+	h.asked = &m
+	return p.await(h)
+}
+
+// AcceptTakeback implements player.TakebackResponder by waiting for the
+// person to Submit whether to let the requester take back its last decision,
+// which is refused if they do not answer in time.
+func (p seated) AcceptTakeback(s state.State, requester seat.Seat) bool {
+	p.t.mu.Lock()
+	h := p.t.people[p.st]
+	h.takeback = requester
+	answer := p.await(h)
+	return len(answer) == 1 && answer[0] == AcceptTakeback
+}
+
+// await tells the person they have been asked and waits for their answer,
+// returning player.Forfeit if it does not come in time or player.Abandon if
+// the Table is closed first. It must be called with t.mu held, and unlocks
+// it.
+func (p seated) await(h *person) []int {
+	var timeout <-chan time.Time
+	if d := p.t.config.Timeout; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	p.t.notify()
+	p.t.mu.Unlock()
+	var answer []int
+	select {
+	case answer = <-h.answers:
+		return answer
+	case <-timeout:
+		answer = []int{player.Forfeit}
+	case <-p.t.ctx.Done():
+		answer = []int{player.Abandon}
+	}
+	p.t.mu.Lock()
+	defer p.t.mu.Unlock()
+	h.asked, h.takeback = nil, seat.None
+	// Drop an answer Submitted as the wait ended.
+	select {
+	case <-h.answers:
+	default:
+	}
+	p.t.notify()
+	return answer
+}
+
+// Update implements player.Player. The Table observes the game itself.
+func (p seated) Update(s state.State, t action.Type) {}
+
+// Rejected implements player.RejectionReceiver by showing the person the
+// reason in their Status.
+func (p seated) Rejected(err error) {
+	p.t.mu.Lock()
+	defer p.t.mu.Unlock()
+	p.t.people[p.st].rejected = err.Error()
+	p.t.notify()
+}
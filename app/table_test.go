@@ -0,0 +1,176 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+func TestTakeback(t *testing.T) {
+	s := NewServer(nil)
+	table, err := s.CreateTable(TableConfig{Players: map[seat.Seat]string{seat.North: "DRW", seat.South: "DRW"}, Seed: 3, Takebacks: true})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	toks := make(map[seat.Seat]string)
+	for _, st := range []seat.Seat{seat.East, seat.West} {
+		if toks[st], err = table.Join(st, st.String(), ""); err != nil {
+			t.Fatalf("Join: %s", err)
+		}
+		if err := table.SetReady(toks[st], true); err != nil {
+			t.Fatalf("SetReady: %s", err)
+		}
+	}
+	status := func(st seat.Seat) (Status, <-chan struct{}) {
+		s, changed, err := table.Status(toks[st])
+		if err != nil {
+			t.Fatalf("Status: %s", err)
+		}
+		return s, changed
+	}
+	submit := func(st seat.Seat, selection []int) {
+		if err := table.Submit(toks[st], selection); err != nil {
+			t.Fatalf("Submit %v for %s: %s", selection, st, err)
+		}
+	}
+
+	// East makes a decision, then asks to take it back twice: West refuses
+	// the first time and accepts the second.
+	var first *action.Message
+	var refused, accepted bool
+	for !accepted {
+		east, changed := status(seat.East)
+		west, _ := status(seat.West)
+		switch {
+		case west.Takeback == seat.East && !refused:
+			if err := table.Submit(toks[seat.West], []int{7}); err == nil {
+				t.Errorf("answered a takeback with 7")
+			}
+			submit(seat.West, []int{RefuseTakeback})
+			refused = true
+		case west.Takeback == seat.East:
+			submit(seat.West, []int{AcceptTakeback})
+			accepted = true
+		case west.Asked:
+			submit(seat.West, ai.DRW.Play(*west.State, *west.Message))
+		case east.Asked && first == nil:
+			first = east.Message
+			submit(seat.East, ai.DRW.Play(*east.State, *east.Message))
+		case east.Asked:
+			submit(seat.East, []int{player.Takeback})
+		default:
+			select {
+			case <-changed:
+			case <-time.After(10 * time.Second):
+				t.Fatalf("table stuck at\n%+v", east)
+			}
+		}
+	}
+	east := drive(t, table, toks[seat.East], 0)
+	if east.Message.Type != first.Type || east.Message.Seat != seat.East {
+		t.Errorf("after the takeback East is asked %+v, want %+v again", east.Message, first)
+	}
+	if west, _ := status(seat.West); west.Takeback != seat.None || !west.Takebacks {
+		t.Errorf("West still asked about a takeback: %+v", west)
+	}
+}
+
+// await waits for the Table to satisfy done, failing the test if it takes too
+// long.
+func await(t *testing.T, table *Table, tok string, done func(Status) bool) Status {
+	for {
+		st, changed, err := table.Status(tok)
+		if err != nil {
+			t.Fatalf("Status: %s", err)
+		}
+		if done(st) {
+			return st
+		}
+		select {
+		case <-changed:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("table %s stuck at\n%+v", table.ID, st)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := NewServer(nil)
+	s.Timeout = 10 * time.Millisecond
+	table, err := s.CreateTable(TableConfig{Players: map[seat.Seat]string{seat.North: "DRW", seat.South: "DRW", seat.West: "DRW"}, Seed: 5})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	tok, err := table.Join(seat.East, "ann", "")
+	if err != nil {
+		t.Fatalf("Join: %s", err)
+	}
+	if err := table.SetReady(tok, true); err != nil {
+		t.Fatalf("SetReady: %s", err)
+	}
+	await(t, table, tok, func(st Status) bool { return st.Asked })
+	steps := func() []game.Step {
+		table.mu.Lock()
+		defer table.mu.Unlock()
+		return table.record.Steps
+	}
+	// Without an answer from ann, her choice is made for her and the game
+	// goes on.
+	n := len(steps())
+	st := await(t, table, tok, func(Status) bool { return len(steps()) > n })
+	if got := steps()[n]; got.Message.Seat != seat.East || st.Rejected != "" {
+		t.Errorf("step %+v after the timeout (rejected %q), want East's choice made", got, st.Rejected)
+	}
+}
+
+func TestClose(t *testing.T) {
+	store := NewMemoryStore()
+	first := NewServer(store)
+	table, err := first.CreateTable(TableConfig{Players: map[seat.Seat]string{seat.North: "DRW", seat.South: "DRW", seat.West: "DRW"}, Seed: 8})
+	if err != nil {
+		t.Fatalf("CreateTable: %s", err)
+	}
+	tok, err := table.Join(seat.East, "ann", "")
+	if err != nil {
+		t.Fatalf("Join: %s", err)
+	}
+	if err := table.SetReady(tok, true); err != nil {
+		t.Fatalf("SetReady: %s", err)
+	}
+	before := drive(t, table, tok, 5)
+	closed := make(chan struct{})
+	go func() {
+		first.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Close did not stop the game waiting on ann")
+	}
+	if first.Table(table.ID) != nil {
+		t.Errorf("table %s still hosted after Close", table.ID)
+	}
+	if st, _, _ := table.Status(tok); st.Over || st.Error != "" || st.Asked {
+		t.Errorf("closed table ended up at %+v", st)
+	}
+
+	second := NewServer(store)
+	if err := second.Resume(); err != nil {
+		t.Fatalf("Resume: %s", err)
+	}
+	resumed := second.Table(table.ID)
+	if resumed == nil {
+		t.Fatalf("closed table %s was not resumed", table.ID)
+	}
+	if st := drive(t, resumed, tok, 0); !reflect.DeepEqual(st.Message, before.Message) {
+		t.Errorf("resumed asking %+v, want %+v", st.Message, before.Message)
+	}
+	second.Close()
+}
@@ -1,28 +1,301 @@
 package main
 
 import (
+    "context"
+    "database/sql"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
     "log"
+    "net"
+    "net/http"
+    "os"
+    "os/signal"
+    "runtime"
+    "strings"
+    "syscall"
+    "time"
 
-    "dr2w.com/hf/game"
-    "dr2w.com/hf/ai"
-    //"dr2w.com/hf/player"
+    _ "dr2w.com/hf/ai"
+    "dr2w.com/hf/app"
+    "dr2w.com/hf/player"
+    "dr2w.com/hf/model/rules"
     "dr2w.com/hf/model/seat"
+    "dr2w.com/hf/server"
+    "dr2w.com/hf/sim"
 )
 
+const usage = `usage: hf <command> [flags]
+
+Commands:
+    match      play a series of games and report results per partnership
+    players    list the players which may be seated
+    serve      host a game for players joining over the network
+    join       play in a game hosted by serve
+    web        serve tables to play over HTTP
+
+Run "hf <command> -h" for the flags of a command.
+`
+
 func main() {
-    for i := 0; i < 20000; i++ {
-        g, _ := game.New(
-            seat.East,
-            ai.DRW,
-            ai.DRW,
-            ai.DRW,
-	    ai.DRW,
-            // player.Stdio{},
-        )
-        err := g.Resolve()
+    if len(os.Args) < 2 {
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+    var err error
+    switch os.Args[1] {
+    case "match":
+        err = match(os.Args[2:])
+    case "serve":
+        err = serve(os.Args[2:])
+    case "join":
+        err = join(os.Args[2:])
+    case "web":
+        err = web(os.Args[2:])
+    case "players":
+        fmt.Println(strings.Join(player.Names(), "\n"))
+    case "help", "-h", "-help", "--help":
+        fmt.Print(usage)
+    default:
+        fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+        os.Exit(2)
+    }
+    if err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        os.Exit(1)
+    }
+}
+
+// match parses the flags of the match command, plays the requested games and
+// writes the results to stdout.
+func match(args []string) error {
+    var (
+        fs = flag.NewFlagSet("match", flag.ExitOnError)
+        names = make(map[seat.Seat]*string)
+        first = seat.East
+    )
+    for _, st := range seat.Order {
+        names[st] = fs.String(strings.ToLower(st.String()), "DRW", fmt.Sprintf("player in the %s seat", st))
+    }
+    fs.TextVar(&first, "first", seat.East, "seat of the first dealer")
+    seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the first game; game i uses seed+i")
+    games := fs.Int("games", 20000, "number of games to play")
+    parallel := fs.Int("parallel", runtime.NumCPU(), "number of games to play at once")
+    format := fs.String("format", "text", "output format: text or json for a summary, csv for one line per game")
+    variant := fs.String("rules", rules.Standard.Name, fmt.Sprintf("rules to play by: one of %v", rules.Presets()))
+    takebacks := fs.Bool("takebacks", false, "let a player take back its last decision if the others accept")
+    debug := fs.Bool("debug", false, "check every game state for broken invariants")
+    verbose := fs.Bool("v", false, "log every game as it finishes")
+    fs.Parse(args)
+
+    if !*verbose {
+        log.SetOutput(io.Discard)
+    }
+    var seats []string
+    for _, st := range seat.Order {
+        if _, err := player.New(*names[st], st); err != nil {
+            return err
+        }
+        seats = append(seats, *names[st])
+        if *names[st] == "Stdio" {
+            // A human can only sit at one table at a time.
+            *parallel = 1
+        }
+    }
+    r, err := rules.Preset(*variant)
+    if err != nil {
+        return err
+    }
+    if *parallel < 1 {
+        *parallel = 1
+    }
+
+    c := sim.Config{First: first, Games: *games, Seed: *seed, Workers: *parallel, Takebacks: *takebacks, Debug: *debug, Rules: r}
+    for i, st := range seat.Order {
+        name := *names[st]
+        c.Players[i] = func(st seat.Seat) player.Player {
+            p, _ := player.New(name, st)
+            return p
+        }
+    }
+    results := make([]Result, *games)
+    for r := range sim.Run(context.Background(), c) {
+        results[r.Game] = result(r)
+    }
+
+    switch *format {
+    case "text":
+        return Summarize(seats, results).WriteText(os.Stdout)
+    case "json":
+        return Summarize(seats, results).WriteJSON(os.Stdout)
+    case "csv":
+        return WriteCSV(os.Stdout, results)
+    }
+    return fmt.Errorf("unknown output format %q", *format)
+}
+
+// remote is the name given on the command line to a Seat left to a player
+// joining over the network.
+const remote = "Remote"
+
+// serve parses the flags of the serve command, waits for the remote players
+// to join and plays a single game, writing the final score to stdout.
+func serve(args []string) error {
+    var (
+        fs = flag.NewFlagSet("serve", flag.ExitOnError)
+        names = make(map[seat.Seat]*string)
+        first = seat.East
+    )
+    for _, st := range seat.Order {
+        names[st] = fs.String(strings.ToLower(st.String()), remote, fmt.Sprintf("player in the %s seat, or %s to wait for one to join", st, remote))
+    }
+    fs.TextVar(&first, "first", seat.East, "seat of the first dealer")
+    addr := fs.String("addr", ":5555", "address to listen on")
+    seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the game")
+    variant := fs.String("rules", rules.Standard.Name, fmt.Sprintf("rules to play by: one of %v", rules.Presets()))
+    takebacks := fs.Bool("takebacks", false, "let a player take back its last decision if the others accept")
+    reprompts := fs.Int("reprompts", 2, "times a player is asked again after a choice the rules do not allow")
+    timeout := fs.Duration("timeout", 0, "time a remote player is given to answer, or 0 to wait forever")
+    fs.Parse(args)
+
+    r, err := rules.Preset(*variant)
+    if err != nil {
+        return err
+    }
+    c := server.Config{
+        First: first,
+        Seed: *seed,
+        Rules: r,
+        Takebacks: *takebacks,
+        Reprompts: *reprompts,
+        // A remote player which hangs up forfeits its choices rather than
+        // ending the game for everyone else.
+        Forfeit: true,
+        Timeout: *timeout,
+    }
+    for i, st := range seat.Order {
+        name := *names[st]
+        if name == remote {
+            continue
+        }
+        if _, err := player.New(name, st); err != nil {
+            return err
+        }
+        c.Players[i] = func(st seat.Seat) player.Player {
+            p, _ := player.New(name, st)
+            return p
+        }
+    }
+    l, err := net.Listen("tcp", *addr)
+    if err != nil {
+        return err
+    }
+    defer l.Close()
+    fmt.Printf("Listening on %s for %v\n", l.Addr(), c.Remotes())
+    g, err := server.Host(context.Background(), l, c)
+    if err != nil {
+        return err
+    }
+    fmt.Printf("%s %d %s %d\n", seat.NorthSouth, g.State.Score[seat.NorthSouth], seat.EastWest, g.State.Score[seat.EastWest])
+    return nil
+}
+
+// join parses the flags of the join command and plays in the game hosted at
+// the given address until it ends.
+func join(args []string) error {
+    fs := flag.NewFlagSet("join", flag.ExitOnError)
+    addr := fs.String("addr", "localhost:5555", "address of the host")
+    name := fs.String("player", "Stdio", "player to play as")
+    fs.Parse(args)
+
+    if _, err := player.New(*name, seat.None); err != nil {
+        return err
+    }
+    conn, err := net.Dial("tcp", *addr)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+    return player.Relay(conn, func(st seat.Seat) player.Player {
+        p, _ := player.New(*name, st)
+        return p
+    })
+}
+
+// web parses the flags of the web command, resumes the unfinished tables
+// kept from earlier runs and serves tables over HTTP until the server fails
+// or is interrupted, when the games are kept to be resumed.
+func web(args []string) error {
+    fs := flag.NewFlagSet("web", flag.ExitOnError)
+    addr := fs.String("addr", ":8080", "address to listen on")
+    dir := fs.String("dir", "", "directory to keep games in, one file each")
+    db := fs.String("db", "", "database to keep games in, as driver:source, such as sqlite:games.db when built with -tags sqlite")
+    timeout := fs.Duration("timeout", 0, "time a person is given to answer before their choice is made for them, or 0 to wait forever")
+    fs.Parse(args)
+
+    var store app.Store
+    switch {
+    case *dir != "" && *db != "":
+        return fmt.Errorf("keep games in a directory or a database, not both")
+    case *dir != "":
+        s, err := app.NewFileStore(*dir)
+        if err != nil {
+            return err
+        }
+        store = s
+    case *db != "":
+        driver, source, ok := strings.Cut(*db, ":")
+        if !ok {
+            return fmt.Errorf("database %q is not of the form driver:source", *db)
+        }
+        conn, err := sql.Open(driver, source)
+        if err != nil {
+            return err
+        }
+        defer conn.Close()
+        s, err := app.NewSQLStore(conn)
         if err != nil {
-            log.Fatalf("Error in Resolving: %s\n%s", err, g)
+            return err
         }
-        log.Printf("Score: %v (%d rounds)", g.State.Score, g.State.Rounds)
+        store = s
+    }
+    server := app.NewServer(store)
+    server.Timeout = *timeout
+    if err := server.Resume(); err != nil {
+        return err
+    }
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    h := &http.Server{Addr: *addr, Handler: server}
+    go func() {
+        <-ctx.Done()
+        // Stop the games before the connections, which include event
+        // streams that never end by themselves.
+        server.Close()
+        shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        h.Shutdown(shutdown)
+    }()
+    fmt.Printf("Serving tables on %s\n", *addr)
+    if err := h.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+        return err
+    }
+    return nil
+}
+
+// result converts the outcome of a simulated game for reporting.
+func result(r sim.Result) Result {
+    if r.Err != nil {
+        log.Printf("Error in Resolving (replay with -seed=%d -games=1): %s", r.Seed, r.Err)
+        return Result{Seed: r.Seed, Err: r.Err.Error()}
+    }
+    log.Printf("Game %d: North-South %d East-West %d (%d rounds)",
+        r.Seed, r.Score[seat.NorthSouth], r.Score[seat.EastWest], r.Rounds)
+    return Result{
+        Seed:       r.Seed,
+        NorthSouth: r.Score[seat.NorthSouth],
+        EastWest:   r.Score[seat.EastWest],
+        Rounds:     r.Rounds,
     }
 }
@@ -2,26 +2,118 @@
 package game
 
 import (
+    "errors"
     "fmt"
     "log"
     "bytes"
+    "math/rand"
+    "sort"
     "strings"
+    "time"
 
+    "dr2w.com/hf/model/deck"
+    "dr2w.com/hf/model/rules"
     "dr2w.com/hf/model/seat"
     "dr2w.com/hf/player"
     "dr2w.com/hf/model/state"
     "dr2w.com/hf/model/action"
 )
 
-const (
-    winningScore = 52
-    terribleScore = -104
-)
+// ErrAbandoned is returned by Advance when a Player abandons the Game.
+var ErrAbandoned = errors.New("game abandoned")
 
 type Game struct {
     Players map[seat.Seat]player.Player
     State state.State
     Message action.Message
+    // Seed determines every random choice made during the Game; a Game
+    // created with NewSeeded and the same Seed and Players replays exactly.
+    Seed int64
+    // Record logs every Message sent and every selection returned.
+    Record Record
+    // Takebacks allows a Player to take back its last decision by
+    // responding with player.Takeback, if every other Player accepts.
+    Takebacks bool
+    // Reprompts is the number of times a Player whose response the rules do
+    // not allow is asked again before the Game gives up on it.
+    Reprompts int
+    // Forfeit makes a Player which the Game gives up on forfeit its choice,
+    // which the Game makes for it, rather than ending the Game with an
+    // error.
+    Forfeit bool
+    // Debug makes the Game check every State it advances to with
+    // state.Validate, and end with an action.ErrInvariant error rather than
+    // carry on from a State which breaks the rules of the game.
+    Debug bool
+    // Observer, if set, is called by Resolve after every step with a copy
+    // of the State and the Message which follows, so that the Game may be
+    // watched from other goroutines without touching it.
+    Observer func(s state.State, m action.Message)
+    // rand is handed to the Players for their own random choices, so that
+    // they do not disturb the randomness drawn by the actions.
+    rand *rand.Rand
+    // source is the source of the randomness drawn by the actions, which
+    // is rewound along with the State.
+    source *countingSource
+    // history holds every earlier turn of the Game, most recent last, and
+    // future the turns undone since, most recently undone last.
+    history []turn
+    future []turn
+}
+
+// turn is a point in the Game which can be returned to. Since
+// action.NextState never modifies its input, a State may be kept as is.
+type turn struct {
+    State state.State
+    Message action.Message
+    // steps and decks are the lengths of the Record at this point, and
+    // draws the number of values drawn from the Game's source.
+    steps, decks int
+    draws uint64
+}
+
+// countingSource is a rand.Source which counts the values drawn from it, so
+// that it can be rewound to an earlier position.
+type countingSource struct {
+    src rand.Source64
+    seed int64
+    draws uint64
+}
+
+// newCountingSource returns a countingSource drawing the same values as
+// rand.NewSource(seed).
+func newCountingSource(seed int64) *countingSource {
+    return &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+}
+
+// Int63 implements rand.Source.
+func (c *countingSource) Int63() int64 {
+    c.draws++
+    return c.src.Int63()
+}
+
+// Uint64 implements rand.Source64.
+func (c *countingSource) Uint64() uint64 {
+    c.draws++
+    return c.src.Uint64()
+}
+
+// Seed implements rand.Source.
+func (c *countingSource) Seed(seed int64) {
+    c.src.Seed(seed)
+    c.seed, c.draws = seed, 0
+}
+
+// rewind returns the source to the position it was at after the given
+// number of draws. Every value drawn advances the underlying source by one
+// step, however it was drawn.
+func (c *countingSource) rewind(draws uint64) {
+    if draws < c.draws {
+        c.Seed(c.seed)
+    }
+    for c.draws < draws {
+        c.Int63()
+    }
 }
 
 func (g *Game) String() string {
@@ -33,6 +125,7 @@ func (g *Game) String() string {
         names = append(names, fmt.Sprintf("%s: %s", seat, player))
     }
     b.WriteString(strings.Join(names, " | ") + "\n")
+    b.WriteString(fmt.Sprintf("Seed: %d\n", g.Seed))
     b.WriteString(fmt.Sprintf("State:\n%s", g.State))
     b.WriteString(fmt.Sprintf("Message:\n%s", g.Message))
     return b.String()
@@ -40,8 +133,9 @@ func (g *Game) String() string {
 
 // Over returns true iff the Game's state is a terminal one.
 func (g *Game) Over() bool {
-    for _, score := range g.State.Score {
-        if score > winningScore || score < terribleScore {
+    r := g.State.Rules()
+    for _, tm := range seat.Teams {
+        if score := g.State.Score[tm]; score > r.WinningScore || score < r.LosingScore {
             return true
         }
     }
@@ -55,42 +149,271 @@ func (g *Game) Resolve() error {
         if err := g.Advance(); err != nil {
             return err
         }
-        for _, p := range g.Players {
-            p.Update(g.State, g.Message.Type)
+        if g.Observer != nil {
+            m := g.Message
+            m.Options = append([]int(nil), g.Message.Options...)
+            g.Observer(g.State.Clone(), m)
+        }
+        for st, p := range g.Players {
+            p.Update(g.playerState(st), g.Message.Type)
         }
         //log.Printf("Game Advanced to:\n%s", g)
     }
+    g.Record.Score = make(map[seat.Team]int)
+    for tm, sc := range g.State.Score {
+        g.Record.Score[tm] = sc
+    }
     return nil
 }
 
-// Advance advances the Game one step.
+// playerState returns the State as it is presented to the Player in the given
+// Seat, which cannot see the other Players' cards or the Deck, with the
+// Players' own source of randomness.
+func (g *Game) playerState(st seat.Seat) state.State {
+    s := g.State.View(st)
+    if g.rand != nil {
+        s.Rand = g.rand
+    }
+    return s
+}
+
+// Advance advances the Game one step. If the Player asked responds with
+// player.Takeback, the Game instead returns to that Player's last decision
+// when the Takebacks rule allows it, and otherwise stays where it is. If it
+// responds with player.Abandon, Advance returns ErrAbandoned without
+// advancing, and with player.Forfeit, the Game makes the choice for it as
+// long as it allows forfeits.
+//
+// A response the rules do not allow is rejected with a *action.ResponseError
+// and the Player asked again, up to Reprompts times. After that the Game
+// makes the choice itself if the Player forfeits, and otherwise returns the
+// error without advancing.
+//
+// In Debug mode the State advanced to is validated first, and the Game does
+// not advance if it is invalid.
 func (g *Game) Advance() error {
-    if g.Message.Seat != seat.None {
-        p := g.Players[g.Message.Seat]
-        response := p.Play(g.State, g.Message)
+    request := g.Message
+    from := g.turn()
+    var response []int
+    var s state.State
+    var m action.Message
+    var err error
+    if request.Seat == seat.None {
+        if s, m, err = action.NextState(g.State, request); err != nil {
+            return err
+        }
+    }
+    for attempt := 0; request.Seat != seat.None; attempt++ {
+        p := g.Players[request.Seat]
+        ask := request
+        ask.Options = append([]int(nil), request.Options...)
+        response = p.Play(g.playerState(request.Seat), ask)
         //log.Printf("Player %s chose %v", p, response)
-        g.Message.Options = response
+        if len(response) == 1 && response[0] == player.Takeback {
+            if err := g.Takeback(request.Seat); err != nil {
+                log.Printf("Takeback refused: %s", err)
+            }
+            return nil
+        }
+        if len(response) == 1 && response[0] == player.Abandon {
+            return ErrAbandoned
+        }
+        if len(response) == 1 && response[0] == player.Forfeit && g.Forfeit {
+            if response, s, m, err = g.forfeit(from); err != nil {
+                return err
+            }
+            break
+        }
+        if s, m, err = g.apply(from, response); err == nil {
+            break
+        }
+        if !action.Illegal(err) {
+            return err
+        }
+        log.Printf("Rejected: %s", err)
+        if attempt < g.Reprompts {
+            if r, ok := p.(player.RejectionReceiver); ok {
+                r.Rejected(err)
+            }
+            continue
+        }
+        if !g.Forfeit {
+            return err
+        }
+        if response, s, m, err = g.forfeit(from); err != nil {
+            return err
+        }
+        break
     }
-    s, m, err := action.NextState(g.State, g.Message)
-    if err != nil {
-        return err
+    if g.Debug {
+        if err := s.Validate(); err != nil {
+            return fmt.Errorf("%w: after %s: %s", action.ErrInvariant, request, err)
+        }
     }
+    g.history = append(g.history, from)
+    g.future = nil
+    g.Record.record(g.State, request, response)
     g.State, g.Message = s, m
     return nil
 }
 
-// initialMessage returns the initial deal message given the first player.
-func initialMessage(first seat.Seat) action.Message {
+// apply validates the response to the request made at the given turn and,
+// if the rules allow it, returns the State and Message which follow. The
+// randomness the actions draw is rewound to the turn first, so that only the
+// response accepted draws any. An error which is the response's fault is a
+// *action.ResponseError.
+func (g *Game) apply(from turn, response []int) (state.State, action.Message, error) {
+    request := from.Message
+    if g.source != nil {
+        g.source.rewind(from.draws)
+    }
+    if err := request.Validate(response); err != nil {
+        return state.State{}, action.Message{}, err
+    }
+    m := request
+    m.Options = response
+    s, next, err := action.NextState(g.State, m)
+    if action.Illegal(err) {
+        return state.State{}, action.Message{}, &action.ResponseError{Request: request, Response: response, Err: err}
+    }
+    return s, next, err
+}
+
+// forfeit makes the choice of a Player which has given up its own: the first
+// option the rules allow or, when several cards are to be discarded, the
+// lowest valued ones.
+func (g *Game) forfeit(from turn) ([]int, state.State, action.Message, error) {
+    request := from.Message
+    var candidates [][]int
+    if request.Expect == 1 {
+        for _, o := range request.Options {
+            candidates = append(candidates, []int{o})
+        }
+    } else {
+        options := append([]int(nil), request.Options...)
+        if h := g.State.Hands[request.Seat]; h != nil {
+            sort.SliceStable(options, func(i, j int) bool {
+                return h.Get(options[i]).TrumpValue(g.State.Trump) < h.Get(options[j]).TrumpValue(g.State.Trump)
+            })
+        }
+        if request.Expect <= len(options) {
+            candidates = append(candidates, options[:request.Expect])
+        }
+    }
+    err := fmt.Errorf("no choice allowed for %s", request)
+    for _, c := range candidates {
+        var s state.State
+        var m action.Message
+        if s, m, err = g.apply(from, c); err == nil {
+            log.Printf("%s forfeits its choice to %v", request.Seat, c)
+            return c, s, m, nil
+        }
+    }
+    return nil, state.State{}, action.Message{}, err
+}
+
+// turn returns the current point in the Game.
+func (g *Game) turn() turn {
+    t := turn{State: g.State, Message: g.Message, steps: len(g.Record.Steps), decks: len(g.Record.Decks)}
+    if g.source != nil {
+        t.draws = g.source.draws
+    }
+    return t
+}
+
+// restore returns the Game to the given turn, rewinding the randomness the
+// actions draw so that a turn taken again draws just what it drew before
+// and the Record still replays.
+func (g *Game) restore(t turn) {
+    g.State, g.Message = t.State, t.Message
+    g.Record.Steps = g.Record.Steps[:t.steps]
+    g.Record.Decks = g.Record.Decks[:t.decks]
+    if g.source != nil {
+        g.source.rewind(t.draws)
+    }
+}
+
+// Undo returns the Game to the turn before the last one taken.
+func (g *Game) Undo() error {
+    if len(g.history) == 0 {
+        return fmt.Errorf("nothing to undo")
+    }
+    g.future = append(g.future, g.turn())
+    g.restore(g.history[len(g.history)-1])
+    g.history = g.history[:len(g.history)-1]
+    return nil
+}
+
+// Redo retakes the last turn undone, unless a new turn has been taken since.
+func (g *Game) Redo() error {
+    if len(g.future) == 0 {
+        return fmt.Errorf("nothing to redo")
+    }
+    g.history = append(g.history, g.turn())
+    g.restore(g.future[len(g.future)-1])
+    g.future = g.future[:len(g.future)-1]
+    return nil
+}
+
+// Takeback returns the Game to the last decision made by the given Seat, if
+// the Takebacks rule is on and every other Player accepts.
+func (g *Game) Takeback(st seat.Seat) error {
+    if !g.Takebacks {
+        return fmt.Errorf("takebacks are not allowed in this game")
+    }
+    last := -1
+    for i, t := range g.history {
+        if t.Message.Seat == st {
+            last = i
+        }
+    }
+    if last < 0 {
+        return fmt.Errorf("%s has no decision to take back", st)
+    }
+    for _, other := range seat.Order {
+        if other == st {
+            continue
+        }
+        if r, ok := g.Players[other].(player.TakebackResponder); ok && !r.AcceptTakeback(g.playerState(other), st) {
+            return fmt.Errorf("%s refused the takeback requested by %s", other, st)
+        }
+    }
+    for len(g.history) > last {
+        if err := g.Undo(); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// InitialMessage returns the initial deal message given the first player.
+func InitialMessage(first seat.Seat) action.Message {
     return action.Message{
             Type: action.Deal,
             Seat: first,
             Options: []int{0},
+            Expect: 1,
         }
 }
 
 // New returns a Game initialized to the starting state for the given first
 // seat and players (ordered by seat.Order).
 func New(first seat.Seat, players ...player.Player) (*Game, error) {
+    return NewSeeded(time.Now().UnixNano(), first, players...)
+}
+
+// NewSeeded behaves like New, but draws all shuffles, forced discards and AI
+// tie-breaks from a source of randomness seeded with the given seed.
+func NewSeeded(seed int64, first seat.Seat, players ...player.Player) (*Game, error) {
+    return NewWithRules(rules.Standard, seed, first, players...)
+}
+
+// NewWithRules behaves like NewSeeded, but plays the given variant of the
+// rules, which must be valid.
+func NewWithRules(r rules.Config, seed int64, first seat.Seat, players ...player.Player) (*Game, error) {
+    if err := r.Validate(); err != nil {
+        return nil, err
+    }
     if len(players) != len(seat.Order) {
         return nil, fmt.Errorf("invalid number of players (%d) supplied to game.New.", len(players))
     }
@@ -98,9 +421,42 @@ func New(first seat.Seat, players ...player.Player) (*Game, error) {
     for i, s := range seat.Order {
         p[s] = players[i]
     }
+    source := newCountingSource(seed)
+    s := state.InitialWithRand(first, rand.New(source))
+    s.Config = r
     return &Game{
         Players: p,
-        State: state.Initial(first),
-        Message: initialMessage(first),
+        State: s,
+        Message: InitialMessage(first),
+        Seed: seed,
+        Record: Record{Seed: seed, First: first, Rules: r},
+        rand: rand.New(rand.NewSource(^seed)),
+        source: source,
     }, nil
 }
+
+// Resume returns a Game which carries on from where the recorded one left
+// off, with the given players (ordered by seat.Order) taking over. The
+// Record may be of a Game which is over, in which case so is the one
+// returned. The turns already taken cannot be undone.
+func Resume(r Record, players ...player.Player) (*Game, error) {
+    rs := r.Rules
+    if rs == (rules.Config{}) {
+        rs = rules.Standard
+    }
+    g, err := NewWithRules(rs, r.Seed, r.First, players...)
+    if err != nil {
+        return nil, err
+    }
+    // Replaying the Record on the Game's own source leaves it where the
+    // recorded Game's was.
+    g.source.rewind(0)
+    s, m, err := walk(r, g.State.Rand, nil)
+    if err != nil {
+        return nil, err
+    }
+    g.State, g.Message = s, m
+    g.Record.Decks = append([]deck.Deck(nil), r.Decks...)
+    g.Record.Steps = append([]Step(nil), r.Steps...)
+    return g, nil
+}
@@ -0,0 +1,444 @@
+package game
+
+import (
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/player"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+// resolved returns a completed Game between four DRW players, every State
+// of which is valid.
+func resolved(t *testing.T, seed int64) *Game {
+	g, err := NewSeeded(seed, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	g.Debug = true
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	return g
+}
+
+func TestNewSeededIsReproducible(t *testing.T) {
+	a, b := resolved(t, 3), resolved(t, 3)
+	if !reflect.DeepEqual(a.Record, b.Record) {
+		t.Errorf("same seed produced different records:\n%+v\nvs\n%+v", a.Record, b.Record)
+	}
+	if !reflect.DeepEqual(a.State.Score, b.State.Score) {
+		t.Errorf("same seed produced different scores: %v vs %v", a.State.Score, b.State.Score)
+	}
+}
+
+func TestScoresheet(t *testing.T) {
+	for _, seed := range []int64{0, 1, 2} {
+		g := resolved(t, seed)
+		sh := g.State.Scoresheet
+		if len(sh) != g.State.Rounds {
+			t.Fatalf("Seed %d: %d rounds played, but %d on the scoresheet", seed, g.State.Rounds, len(sh))
+		}
+		totals := sh.Totals()
+		if !reflect.DeepEqual(totals[len(totals)-1], g.State.Score) {
+			t.Errorf("Seed %d: scoresheet totals %v, score %v", seed, totals[len(totals)-1], g.State.Score)
+		}
+		for _, r := range sh {
+			if r.PassedOut() {
+				continue
+			}
+			taken := 0
+			for _, c := range r.Tricks {
+				taken += c.Points
+			}
+			if p := r.Points[seat.NorthSouth] + r.Points[seat.EastWest]; p != taken {
+				t.Errorf("Seed %d, round %d: tricks hold %d points, teams took %d", seed, r.Round, taken, p)
+			}
+			if r.Made != (r.Delta[r.Bidder.Team()] > 0) {
+				t.Errorf("Seed %d, round %d: made is %v with a change of %d", seed, r.Round, r.Made, r.Delta[r.Bidder.Team()])
+			}
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	for _, seed := range []int64{0, 1, 2} {
+		g := resolved(t, seed)
+		s, err := Replay(g.Record)
+		if err != nil {
+			t.Errorf("Seed %d: unexpected error: %s", seed, err)
+			continue
+		}
+		if s.Rounds != g.State.Rounds {
+			t.Errorf("Seed %d: replayed %d rounds, want %d", seed, s.Rounds, g.State.Rounds)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	g, err := NewWithRules(rules.House, 5, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewWithRules: %s", err)
+	}
+	for i := 0; i < 150; i++ {
+		if err := g.Advance(); err != nil {
+			t.Fatalf("Advance: %s\n%s", err, g)
+		}
+	}
+	resumed, err := Resume(g.Record, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("Resume: %s", err)
+	}
+	want, got := g.State.Clone(), resumed.State.Clone()
+	want.Rand, got.Rand = nil, nil
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(resumed.Message, g.Message) {
+		t.Fatalf("resumed at\n%s%s\nwant\n%s%s", got, resumed.Message, want, g.Message)
+	}
+	if err := resumed.Undo(); err == nil {
+		t.Errorf("undid a turn taken before resuming")
+	}
+	resumed.Debug = true
+	if err := resumed.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, resumed)
+	}
+	if _, err := Replay(resumed.Record); err != nil {
+		t.Errorf("Replay: %s", err)
+	}
+}
+
+func TestReplayDetectsTampering(t *testing.T) {
+	g := resolved(t, 4)
+	r := g.Record
+	r.Score = map[seat.Team]int{seat.NorthSouth: 1000}
+	if _, err := Replay(r); err == nil {
+		t.Errorf("Replay accepted a record with the wrong final score")
+	}
+	r = g.Record
+	r.Decks = r.Decks[:1]
+	if _, err := Replay(r); err == nil {
+		t.Errorf("Replay accepted a record with missing decks")
+	}
+}
+
+// peeker is a Player which records whether it was ever shown a card it
+// should not be able to see.
+type peeker struct {
+	player.Player
+	seat  seat.Seat
+	peeks *int
+}
+
+func (p peeker) Play(s state.State, m action.Message) []int {
+	for _, c := range s.Deck {
+		if c != (card.Card{}) {
+			*p.peeks++
+		}
+	}
+	for st, h := range s.Hands {
+		for _, c := range *h {
+			if st != p.seat && c != (card.Card{}) {
+				*p.peeks++
+			}
+		}
+	}
+	return p.Player.Play(s, m)
+}
+
+func TestPlayersSeeOnlyTheirView(t *testing.T) {
+	peeks := 0
+	var players []player.Player
+	for _, st := range seat.Order {
+		players = append(players, peeker{ai.DRW, st, &peeks})
+	}
+	g, err := NewSeeded(6, seat.East, players...)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	if peeks > 0 {
+		t.Errorf("players were shown %d hidden cards", peeks)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	g, err := NewSeeded(7, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	if err := g.Undo(); err == nil {
+		t.Errorf("Undo succeeded before any turn was taken")
+	}
+	var states []string
+	for i := 0; i < 30; i++ {
+		states = append(states, g.State.String()+g.Message.String())
+		if err := g.Advance(); err != nil {
+			t.Fatalf("Advance: %s", err)
+		}
+	}
+	states = append(states, g.State.String()+g.Message.String())
+	for i := 0; i < 10; i++ {
+		if err := g.Undo(); err != nil {
+			t.Fatalf("Undo: %s", err)
+		}
+	}
+	if got := g.State.String() + g.Message.String(); got != states[20] || len(g.Record.Steps) != 20 {
+		t.Errorf("Undo returned to the wrong turn (%d steps recorded):\n%s\nwant\n%s", len(g.Record.Steps), got, states[20])
+	}
+	for i := 0; i < 10; i++ {
+		if err := g.Redo(); err != nil {
+			t.Fatalf("Redo: %s", err)
+		}
+	}
+	if got := g.State.String() + g.Message.String(); got != states[30] || len(g.Record.Steps) != 30 {
+		t.Errorf("Redo returned to the wrong turn (%d steps recorded):\n%s\nwant\n%s", len(g.Record.Steps), got, states[30])
+	}
+	if err := g.Redo(); err == nil {
+		t.Errorf("Redo succeeded with nothing undone")
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s", err)
+	}
+	if _, err := Replay(g.Record); err != nil {
+		t.Errorf("Replay after Undo and Redo: %s", err)
+	}
+}
+
+func TestUndoRewindsRandomness(t *testing.T) {
+	g, err := NewSeeded(9, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	// Play through the end of the first round, which shuffles the next
+	// round's Deck.
+	for g.State.Rounds == 0 {
+		if err := g.Advance(); err != nil {
+			t.Fatalf("Advance: %s", err)
+		}
+	}
+	shuffled := g.State.Deck.String()
+	if err := g.Undo(); err != nil {
+		t.Fatalf("Undo: %s", err)
+	}
+	// Take the turn again rather than redoing it.
+	if err := g.Advance(); err != nil {
+		t.Fatalf("Advance: %s", err)
+	}
+	if got := g.State.Deck.String(); got != shuffled {
+		t.Errorf("retaken turn shuffled\n%s\nwant\n%s", got, shuffled)
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s", err)
+	}
+	replayed, err := Replay(g.Record)
+	if err != nil {
+		t.Fatalf("Replay after Undo: %s", err)
+	}
+	want := g.State.Clone()
+	replayed.Rand, want.Rand = nil, nil
+	if !reflect.DeepEqual(replayed, want) {
+		t.Errorf("Replay after Undo reached\n%s\nwant\n%s", replayed, want)
+	}
+}
+
+// taker is a Player which asks for a takeback the first time it is asked
+// to play a card, and accepts or refuses takebacks from others.
+type taker struct {
+	player.Player
+	asked  *bool
+	accept bool
+}
+
+func (p taker) Play(s state.State, m action.Message) []int {
+	if m.Type == action.Play && !*p.asked {
+		*p.asked = true
+		return []int{player.Takeback}
+	}
+	return p.Player.Play(s, m)
+}
+
+func (p taker) AcceptTakeback(s state.State, requester seat.Seat) bool {
+	return p.accept
+}
+
+var takebackTests = []struct {
+	name      string
+	allowed   bool
+	accept    bool
+	takenBack bool
+}{
+	{"Allowed And Accepted", true, true, true},
+	{"Allowed But Refused", true, false, false},
+	{"Not Allowed", false, true, false},
+}
+
+func TestTakeback(t *testing.T) {
+	for _, test := range takebackTests {
+		asked, never := false, true
+		players := []player.Player{
+			taker{ai.DRW, &asked, test.accept},
+			taker{ai.DRW, &never, test.accept},
+			taker{ai.DRW, &never, test.accept},
+			taker{ai.DRW, &never, test.accept},
+		}
+		g, err := NewSeeded(8, seat.East, players...)
+		if err != nil {
+			t.Fatalf("NewSeeded: %s", err)
+		}
+		g.Takebacks = test.allowed
+		for !asked {
+			if err := g.Advance(); err != nil {
+				t.Fatalf("%s: Advance: %s", test.name, err)
+			}
+		}
+		// North's last decision was its Bid or its Discard.
+		m := g.Message
+		takenBack := m.Seat == seat.North && (m.Type == action.Bid || m.Type == action.Discard || m.Type == action.Trump)
+		if takenBack != test.takenBack {
+			t.Errorf("%s: after the takeback request the Game asks %s", test.name, m)
+		}
+		if !test.takenBack && (m.Type != action.Play || m.Seat != seat.North) {
+			t.Errorf("%s: want North asked to play again, got %s", test.name, m)
+		}
+		if err := g.Resolve(); err != nil {
+			t.Fatalf("%s: Resolve: %s", test.name, err)
+		}
+	}
+}
+
+func TestNewWithRules(t *testing.T) {
+	g, err := NewWithRules(rules.House, 9, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewWithRules: %s", err)
+	}
+	g.Debug = true
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	over := false
+	for _, sc := range g.State.Score {
+		over = over || sc > rules.House.WinningScore || sc < rules.House.LosingScore
+	}
+	if !over {
+		t.Errorf("game ended before reaching the house target: %v", g.State.Score)
+	}
+	for _, b := range g.State.Bids {
+		if !rules.House.Allows(b) {
+			t.Errorf("bid %s made under house rules", b)
+		}
+	}
+	if _, err := Replay(g.Record); err != nil {
+		t.Errorf("Replay: %s", err)
+	}
+	bad := rules.House
+	bad.HandSize = 0
+	if _, err := NewWithRules(bad, 9, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW); err == nil {
+		t.Errorf("NewWithRules accepted invalid rules")
+	}
+}
+
+// cheater is a Player which answers its first bad requests to play a card
+// with a card it was not offered, and counts the times it is told so.
+type cheater struct {
+	player.Player
+	bad      *int
+	rejected *int
+}
+
+func (p cheater) Play(s state.State, m action.Message) []int {
+	if m.Type == action.Play && *p.bad > 0 {
+		*p.bad--
+		return []int{99}
+	}
+	return p.Player.Play(s, m)
+}
+
+func (p cheater) Rejected(err error) {
+	*p.rejected++
+}
+
+var invalidResponseTests = []struct {
+	name      string
+	bad       int
+	reprompts int
+	forfeit   bool
+	err       bool
+	rejected  int
+}{
+	{"Aborts By Default", 1, 0, false, true, 0},
+	{"Reprompted", 2, 2, false, false, 2},
+	{"Out Of Reprompts", 3, 2, false, true, 2},
+	{"Forfeits", 1000, 1, true, false, -1},
+}
+
+func TestInvalidResponse(t *testing.T) {
+	for _, test := range invalidResponseTests {
+		bad, rejected := test.bad, 0
+		g, err := NewSeeded(4, seat.East, cheater{ai.DRW, &bad, &rejected}, ai.DRW, ai.DRW, ai.DRW)
+		if err != nil {
+			t.Fatalf("%s: NewSeeded: %s", test.name, err)
+		}
+		g.Reprompts, g.Forfeit = test.reprompts, test.forfeit
+		err = g.Resolve()
+		if test.err {
+			var re *action.ResponseError
+			if !errors.As(err, &re) || !errors.Is(err, action.ErrNotOffered) || re.Request.Seat != seat.North {
+				t.Errorf("%s: want a rejected response from North, got %v", test.name, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if test.rejected >= 0 && rejected != test.rejected {
+			t.Errorf("%s: want %d rejections, got %d", test.name, test.rejected, rejected)
+		}
+		if !test.err {
+			if _, err := Replay(g.Record); err != nil {
+				t.Errorf("%s: Replay: %s", test.name, err)
+			}
+		}
+	}
+}
+
+// fixed is a Player which answers every request the same way.
+type fixed []int
+
+func (p fixed) Play(s state.State, m action.Message) []int { return p }
+func (p fixed) Update(s state.State, t action.Type)       {}
+
+func TestForfeitAndAbandon(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		north   fixed
+		forfeit bool
+		want    error
+	}{
+		{"Forfeit", fixed{player.Forfeit}, true, nil},
+		{"Forfeit Not Allowed", fixed{player.Forfeit}, false, action.ErrNotOffered},
+		{"Abandon", fixed{player.Abandon}, true, ErrAbandoned},
+	} {
+		g, err := NewSeeded(6, seat.East, test.north, ai.DRW, ai.DRW, ai.DRW)
+		if err != nil {
+			t.Fatalf("%s: NewSeeded: %s", test.name, err)
+		}
+		g.Forfeit = test.forfeit
+		if err := g.Resolve(); !errors.Is(err, test.want) {
+			t.Errorf("%s: Resolve returned %v, want %v", test.name, err, test.want)
+		}
+		if _, err := Resume(g.Record, ai.DRW, ai.DRW, ai.DRW, ai.DRW); err != nil {
+			t.Errorf("%s: Resume: %s", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package game
+
+import (
+    "fmt"
+    "math/rand"
+
+    "dr2w.com/hf/model/action"
+    "dr2w.com/hf/model/deck"
+    "dr2w.com/hf/model/rules"
+    "dr2w.com/hf/model/seat"
+    "dr2w.com/hf/model/state"
+)
+
+// Step is a single exchange in the game loop: the Message sent to the Player
+// in Message.Seat and the selection that Player returned.
+type Step struct {
+    Message  action.Message
+    Response []int
+}
+
+// Record is a complete log of a Game, sufficient to re-drive
+// action.NextState from the initial state to the final one.
+type Record struct {
+    // Seed seeds the randomness used by the actions themselves (forced
+    // discards during the redeal).
+    Seed  int64
+    First seat.Seat
+    // Rules are the rules the Game was played under; the zero Config
+    // stands for rules.Standard.
+    Rules rules.Config
+    // Decks holds the order of the Deck at the start of each round.
+    Decks []deck.Deck
+    Steps []Step
+    // Score is the final score reached by the recorded Game.
+    Score map[seat.Team]int
+}
+
+// record appends the exchange of m and response to the Record, noting the
+// Deck order whenever a new round is about to be dealt.
+func (r *Record) record(s state.State, m action.Message, response []int) {
+    if m.Type == action.Deal {
+        r.Decks = append(r.Decks, append(deck.Deck{}, s.Deck...))
+    }
+    m.Options = append([]int(nil), m.Options...)
+    if response != nil {
+        response = append([]int{}, response...)
+    }
+    r.Steps = append(r.Steps, Step{m, response})
+}
+
+// sameRequest returns true iff the two Messages make the same request.
+func sameRequest(a, b action.Message) bool {
+    if a.Type != b.Type || a.Seat != b.Seat || a.Expect != b.Expect || len(a.Options) != len(b.Options) {
+        return false
+    }
+    for i := range a.Options {
+        if a.Options[i] != b.Options[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// Replay re-drives action.NextState through every Step in the Record,
+// dealing from the recorded Decks, and returns the final State. It returns
+// an error if any Message differs from the one recorded or if the final
+// Score does not match the recorded Score.
+func Replay(r Record) (state.State, error) {
+    s, err := Walk(r, nil)
+    if err != nil {
+        return s, err
+    }
+    for _, tm := range seat.Teams {
+        if s.Score[tm] != r.Score[tm] {
+            return s, fmt.Errorf("replay reached score %v, recorded %v", s.Score, r.Score)
+        }
+    }
+    return s, nil
+}
+
+// Walk re-drives action.NextState through every Step in the Record, dealing
+// from the recorded Decks, and returns the final State. If visit is non-nil
+// it is called with the State each Step was taken from before that Step is
+// applied. Walk returns an error if any Message differs from the one recorded.
+func Walk(r Record, visit func(s state.State, step Step) error) (state.State, error) {
+    s, _, err := walk(r, rand.New(rand.NewSource(r.Seed)), visit)
+    return s, err
+}
+
+// walk behaves like Walk, drawing the randomness of the actions from rng,
+// which must be seeded with the Record's Seed, but also returns the Message
+// which follows the last Step.
+func walk(r Record, rng *rand.Rand, visit func(s state.State, step Step) error) (state.State, action.Message, error) {
+    s := state.InitialWithRand(r.First, rng)
+    s.Config = r.Rules
+    m := InitialMessage(r.First)
+    round := 0
+    for i, step := range r.Steps {
+        if !sameRequest(m, step.Message) {
+            return s, m, fmt.Errorf("replay diverged at step %d: got %s, recorded %s", i, m, step.Message)
+        }
+        if m.Type == action.Deal {
+            if round >= len(r.Decks) {
+                return s, m, fmt.Errorf("replay reached round %d but only %d decks were recorded", round+1, len(r.Decks))
+            }
+            s.Deck = append(deck.Deck{}, r.Decks[round]...)
+            round++
+        }
+        if visit != nil {
+            if err := visit(s, step); err != nil {
+                return s, m, err
+            }
+        }
+        if m.Seat != seat.None {
+            m.Options = append([]int(nil), step.Response...)
+        }
+        var err error
+        s, m, err = action.NextState(s, m)
+        if err != nil {
+            return s, m, fmt.Errorf("replay failed at step %d (%s): %w", i, step.Message, err)
+        }
+    }
+    return s, m, nil
+}
@@ -39,6 +39,25 @@ func (t Type) String() string {
 	return typeNames[t]
 }
 
+// MarshalText implements encoding.TextMarshaler using the Type's name.
+func (t Type) MarshalText() ([]byte, error) {
+	if _, ok := typeNames[t]; !ok {
+		return nil, fmt.Errorf("unable to marshal unknown action type %d", int(t))
+	}
+	return []byte(typeNames[t]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Type names.
+func (t *Type) UnmarshalText(text []byte) error {
+	for tp, name := range typeNames {
+		if name == string(text) {
+			*t = tp
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action type %q", text)
+}
+
 // SelectionRange returns a slice of Selections including all between start and
 // end, not including end.
 func SelectionRange(start, end int) (s []int) {
@@ -67,7 +86,7 @@ func (m Message) String() string {
 // than one value or no values exist.
 func (m Message) Selection() (int, error) {
 	if len(m.Options) != 1 {
-		return 0, fmt.Errorf("expected single option in message (%s).", m)
+		return 0, fmt.Errorf("%w: expected a single selection, got %d", ErrWrongCount, len(m.Options))
 	}
 	return m.Options[0], nil
 }
@@ -84,11 +103,23 @@ var actionMap = map[Type]Func{
     ReDeal: redeal,
     Discard: discard,
     Play: play,
+    ThrowIn: throwIn,
     Score: score,
 }
 
 // NextState converts a State and an Action into the next State and Action.
+// The given State is never modified: the action is applied to a Clone, so
+// that callers may branch from, or return to, any State they hold. Any error
+// is a *StepError wrapping one of the categories of error in this package.
 func NextState(s state.State, m Message) (state.State, Message, error) {
-	newState, newMessage, e := actionMap[m.Type](s, m)
-	return newState, newMessage, e
+	f, ok := actionMap[m.Type]
+	if !ok {
+		return state.State{}, Message{}, &StepError{m, fmt.Errorf("%w: unknown action type %d", ErrInvariant, int(m.Type))}
+	}
+	m.Options = append([]int(nil), m.Options...)
+	newState, newMessage, e := f(s.Clone(), m)
+	if e != nil {
+		return state.State{}, Message{}, &StepError{m, e}
+	}
+	return newState, newMessage, nil
 }
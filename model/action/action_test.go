@@ -0,0 +1,75 @@
+package action
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+func TestMessageJSON(t *testing.T) {
+	for tp := range typeNames {
+		want := Message{tp, seat.West, []int{0, 2, 3}, 1}
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tp, err)
+			continue
+		}
+		var got Message
+		if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: round trip through %s gave %v (%v)", tp, b, got, err)
+		}
+	}
+}
+
+// choose returns a valid selection for m: the lowest valued cards when
+// discarding, and otherwise the last Options offered.
+func choose(s state.State, m Message) []int {
+	if m.Type == Discard {
+		h := s.Hands[m.Seat]
+		options := append([]int(nil), m.Options...)
+		sort.SliceStable(options, func(i, j int) bool {
+			return h.Get(options[i]).TrumpValue(s.Trump) < h.Get(options[j]).TrumpValue(s.Trump)
+		})
+		return options[:m.Expect]
+	}
+	return m.Options[len(m.Options)-m.Expect:]
+}
+
+func TestNextStateLeavesInputUnchanged(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		s := state.Seeded(seat.North, seed)
+		m := Message{Deal, seat.North, []int{0}, 1}
+		for rounds := s.Rounds; s.Rounds == rounds; {
+			if m.Seat != seat.None {
+				response := choose(s, m)
+				if err := m.Validate(response); err != nil {
+					t.Fatalf("Seed %d: %s", seed, err)
+				}
+				m.Options = response
+			}
+			before, err := json.Marshal(s)
+			if err != nil {
+				t.Fatalf("Seed %d: unexpected error: %s", seed, err)
+			}
+			clone := s.Clone()
+			next, nextMessage, err := NextState(s, m)
+			if err != nil {
+				t.Fatalf("Seed %d: %s: unexpected error: %s", seed, m, err)
+			}
+			if after, _ := json.Marshal(s); string(after) != string(before) {
+				t.Fatalf("Seed %d: %s modified its input State:\n%s\nbecame\n%s", seed, m.Type, before, after)
+			}
+			if !reflect.DeepEqual(clone, s) {
+				t.Fatalf("Seed %d: %s modified a Clone of its input State", seed, m.Type)
+			}
+			if err := next.Validate(); err != nil {
+				t.Fatalf("Seed %d: %s led to an invalid State: %s\n%s", seed, m, err, next)
+			}
+			s, m = next, nextMessage
+		}
+	}
+}
@@ -19,15 +19,25 @@ func bids(s state.State, m Message) (state.State, Message, error) {
 	if err != nil {
 		return state.State{}, Message{}, err
 	}
-	if sel >= len(bid.Values) {
-		return state.State{}, Message{}, fmt.Errorf("unable to process invalid bid Selection (%d) for %s", sel, s)
+	if _, ok := s.Bids[m.Seat]; ok {
+		return state.State{}, Message{}, fmt.Errorf("%w: %s has already bid", ErrWrongSeat, m.Seat)
+	}
+	if sel < 0 || sel >= len(bid.Values) || !s.Rules().Allows(bid.Values[sel]) {
+		return state.State{}, Message{}, fmt.Errorf("%w: bid selection %d is not allowed", ErrIllegal, sel)
+	}
+	if bid.Values[sel] == bid.Pass && stuck(s, m.Seat) {
+		return state.State{}, Message{}, fmt.Errorf("%w: %s is stuck and unable to Pass", ErrIllegal, m.Seat)
+	}
+	if _, high := bidWinner(s.Bids); bid.Values[sel] != bid.Pass && bid.Values[sel] <= high {
+		return state.State{}, Message{}, fmt.Errorf("%w: a bid of %s does not beat %s", ErrIllegal, bid.Values[sel], high)
 	}
 	s.Bids[m.Seat] = bid.Values[sel]
 
 	if len(s.Bids) == len(seat.Order) {
-		// If everyone passed, we skip playing this round.
+		// If everyone passed, we skip playing this round. This can't happen
+		// when the dealer is stuck.
 		if _, b := bidWinner(s.Bids); b == bid.Pass {
-    			next := s.NextRound(make(map[seat.Seat]int))
+    			next := s.NextRound(state.RoundResult{Bidder: seat.None, Bid: bid.Pass})
 			return next, Message{Deal, next.Dealer, []int{0}, 1}, nil
 		}
 		return s, reqForChooseSuit(s), nil
@@ -52,10 +62,10 @@ func bidWinner(m map[seat.Seat]bid.Bid) (s seat.Seat, b bid.Bid) {
 func reqForNextBid(s state.State) (Message, error) {
 	st, b := bidWinner(s.Bids)
 	if st == seat.None {
-		return Message{}, fmt.Errorf("reqForBid called on State with no Bids (%s).", s)
+		return Message{}, fmt.Errorf("%w: reqForNextBid called with no bids made", ErrInvariant)
 	}
 	if len(s.Bids) >= len(seat.Order) {
-		return Message{}, fmt.Errorf("reqForBid called with all bids made (%s).", s)
+		return Message{}, fmt.Errorf("%w: reqForNextBid called with all bids made", ErrInvariant)
 	}
 	for {
 		st = st.Next()
@@ -66,11 +76,38 @@ func reqForNextBid(s state.State) (Message, error) {
 	return Message{
 		Type:    Bid,
 		Seat:    st,
-		Options: append([]int{int(bid.Pass)}, SelectionRange(int(b)+1, len(bid.Values))...),
+		Options: bidOptions(s, st, b),
 		Expect: 1,
 	}, nil
 }
 
+// bidOptions returns every bid allowed by the rules of the State which the
+// given Seat may make over the given bid: Pass, unless the Seat is stuck,
+// and every higher bid.
+func bidOptions(s state.State, st seat.Seat, over bid.Bid) []int {
+	var options []int
+	for _, b := range s.Rules().Ladder() {
+		if b > over || b == bid.Pass && !stuck(s, st) {
+			options = append(options, int(b))
+		}
+	}
+	return options
+}
+
+// stuck returns true iff the rules of the State stick the dealer, the given
+// Seat is the dealer and every other Seat has passed, so the Seat must bid.
+func stuck(s state.State, st seat.Seat) bool {
+	if !s.Rules().StickTheDealer || st != s.Dealer {
+		return false
+	}
+	for _, other := range seat.Order {
+		if b, ok := s.Bids[other]; other != st && (!ok || b != bid.Pass) {
+			return false
+		}
+	}
+	return true
+}
+
 // reqForChooseSuit takes a state which has all bids completed and
 // returns the corresponding player Message.
 func reqForChooseSuit(s state.State) Message {
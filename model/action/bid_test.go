@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/rules"
 	"dr2w.com/hf/model/seat"
 	"dr2w.com/hf/model/state"
 )
@@ -49,6 +50,34 @@ var bidsTests = []struct {
 		Message{Type: Trump, Seat: seat.North, Options: []int{0, 1, 2, 3}, Expect: 1},
 		false,
 	},
+	{
+		"Stuck dealer can't Pass",
+		state.State{
+			Dealer: seat.West,
+			Bids:   map[seat.Seat]bid.Bid{seat.North: bid.Pass, seat.East: bid.Pass, seat.South: bid.Pass},
+			Config: rules.House,
+		},
+		Message{Seat: seat.West, Options: []int{0}, Expect: 1},
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Eight => Seven",
+		state.State{Bids: map[seat.Seat]bid.Bid{seat.West: bid.B8}},
+		Message{Seat: seat.North, Options: []int{2}, Expect: 1}, // B7
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Eight => Eight",
+		state.State{Bids: map[seat.Seat]bid.Bid{seat.West: bid.B8}},
+		Message{Seat: seat.North, Options: []int{3}, Expect: 1}, // B8
+		state.State{},
+		Message{},
+		true,
+	},
 }
 
 func TestBids(t *testing.T) {
@@ -145,6 +174,50 @@ var reqForNextBidTests = []struct {
 		},
 		false,
 	},
+	{
+		"House Rules",
+		state.State{
+			Bids:   map[seat.Seat]bid.Bid{seat.North: bid.Pass},
+			Config: rules.House,
+		},
+		Message{
+			Type:    Bid,
+			Seat:    seat.East,
+			Options: []int{0, 2, 3, 4, 5, 6, 7, 8, 9, 11},
+			Expect:  1,
+		},
+		false,
+	},
+	{
+		"Stuck Dealer",
+		state.State{
+			Dealer: seat.West,
+			Bids:   map[seat.Seat]bid.Bid{seat.North: bid.Pass, seat.East: bid.Pass, seat.South: bid.Pass},
+			Config: rules.House,
+		},
+		Message{
+			Type:    Bid,
+			Seat:    seat.West,
+			Options: []int{2, 3, 4, 5, 6, 7, 8, 9, 11},
+			Expect:  1,
+		},
+		false,
+	},
+	{
+		"Dealer not stuck after a bid",
+		state.State{
+			Dealer: seat.West,
+			Bids:   map[seat.Seat]bid.Bid{seat.North: bid.Pass, seat.East: bid.B7, seat.South: bid.Pass},
+			Config: rules.House,
+		},
+		Message{
+			Type:    Bid,
+			Seat:    seat.West,
+			Options: []int{0, 3, 4, 5, 6, 7, 8, 9, 11},
+			Expect:  1,
+		},
+		false,
+	},
 	{
 		"Everyone bid already",
 		state.State{Bids: map[seat.Seat]bid.Bid{seat.North: bid.Pass, seat.East: bid.Pass, seat.South: bid.Pass, seat.West: bid.Pass}},
@@ -10,18 +10,11 @@ import (
 	"dr2w.com/hf/model/state"
 )
 
-const (
-	// cardsPerDeal is the number of cards drawn from the deck at once during a deal.
-	cardsPerDeal = 3
-
-	// dealsPerHand is the number of rounds of dealing to each Hand.
-	dealsPerHand = 3
-)
-
 // deal takes a State with a full Deck and returns a State with a full Hand dealt
-// to each Seat. The Selection is ignored.
+// to each Seat, following the deal pattern of its rules. The Selection is ignored.
 func deal(s state.State, _ Message) (state.State, Message, error) {
-	return dealWithPattern(s, cardsPerDeal, dealsPerHand)
+	r := s.Rules()
+	return dealWithPattern(s, r.CardsPerDeal, r.DealsPerHand)
 }
 
 // dealWithPattern deals cards from the Deck to the Hands according to the specified
@@ -38,7 +31,7 @@ func dealWithPattern(s state.State, cpd int, dph int) (state.State, Message, err
 			cards, err := s.Deck.Deal(cpd)
 			if err != nil {
 				return state.State{}, Message{},
-                                       fmt.Errorf("Unable to deal %d cards from %v: %s", cpd, s.Deck, err)
+                                       fmt.Errorf("unable to deal %d cards to %s: %w", cpd, st, err)
 			}
 			s.Hands[st].Add(cards...)
 			st = st.Next()
@@ -51,7 +44,7 @@ func dealWithPattern(s state.State, cpd int, dph int) (state.State, Message, err
 	r := Message{
 		Type:    Bid,
 		Seat:    s.Dealer.Next(),
-		Options: SelectionRange(0, len(bid.Values)),
+		Options: bidOptions(s, s.Dealer.Next(), bid.Pass),
 		Expect:  1,
 	}
 	return s, r, nil
@@ -31,26 +31,42 @@ func omit(h hand.Hand, indices []int) *hand.Hand {
 // is made to winner.Next().
 // If called with the winner's seat, discard assumes that the redeal has occurred, applies
 // the winner's discard, then modifies the game state to a valid state for starting play.
+// A Seat which must discard trump exposes its whole trump holding to the table.
 func discard(s state.State, m Message) (state.State, Message, error) {
 	newHand := omit(*s.Hands[m.Seat], m.Options)
 	if err := validateNewHand(s, m, newHand); err != nil {
 		return state.State{}, Message{}, err
 	}
+	var trump card.Set
+	for _, i := range m.Options {
+		c := s.Hands[m.Seat].Get(i)
+		s.Discard(m.Seat, c)
+		if c.Suit == s.Trump {
+			trump = append(trump, c)
+		}
+	}
+	if len(trump) > 0 {
+		s.RevealDiscarded(m.Seat, trump...)
+		s.RevealHeld(m.Seat, card.Set(*newHand).TrumpCards(s.Trump)...)
+	}
 	s.Hands[m.Seat] = newHand
 	return s, nextMessage(s, m.Seat), nil
 }
 
 func validateNewHand(s state.State, m Message, h *hand.Hand) error {
 	winner, _ := s.WinningBid()
-	trump := card.Set(*s.Hands[m.Seat]).TrumpCards(s.Trump)
-	if m.Seat != winner && h.Length() != trump.Length() {
-		return fmt.Errorf("Invalid discard selection. Must select all non-trump. Hand had %d remaining cards, expected %d", h.Length(), trump.Length())
+	keep := card.Set(*s.Hands[m.Seat]).TrumpCards(s.Trump).Length()
+	if keep > s.Rules().HandSize {
+		keep = s.Rules().HandSize
+	}
+	if m.Seat != winner && (h.Length() != keep || card.Set(*h).TrumpCards(s.Trump).Length() != keep) {
+		return fmt.Errorf("%w: must discard all non-trump, but kept %d cards rather than %d trump", ErrDiscardCount, h.Length(), keep)
 	}
-	if m.Seat == winner && h.ExtraCards() != 0 {
-		return fmt.Errorf("Invalid discard selection. Should have no extra cards reminaing, but found %d", h.ExtraCards())
+	if m.Seat == winner && h.ExtraCards(s.Rules().HandSize) != 0 {
+		return fmt.Errorf("%w: kept %d cards more than a hand of %d", ErrDiscardCount, h.ExtraCards(s.Rules().HandSize), s.Rules().HandSize)
 	}
 	if m.Seat == winner && len(s.Deck) > 0 {
-		return fmt.Errorf("Invalid state reached. Discard called for winner with non-empty deck: %v", s.Deck) 
+		return fmt.Errorf("%w: winner asked to discard with %d cards left in the deck", ErrInvariant, len(s.Deck))
 	}
 	return nil
 }
@@ -58,12 +74,9 @@ func validateNewHand(s state.State, m Message, h *hand.Hand) error {
 func nextMessage(s state.State, st seat.Seat) Message {
 	winner, _ := s.WinningBid()
 	if st == winner {
-		return Message{
-			Type:    Play,
-			Seat:    winner,
-			Options: SelectionRange(0, s.Hands[winner].Length()),
-			Expect: 1,
-		}
+		// The winner leads the first trick; next never fails.
+		m, _ := next(s)
+		return m
 	}
 	nextToDiscard := st.Next()
 	if nextToDiscard == winner {
@@ -77,8 +90,8 @@ func nextMessage(s state.State, st seat.Seat) Message {
 	return Message{
 			Type:    Discard,
 			Seat:    nextToDiscard,
-			Options: s.Hands[nextToDiscard].Discards(s.Trump),
-			Expect: s.Hands[nextToDiscard].NumToDiscard(s.Trump),
+			Options: s.Hands[nextToDiscard].Discards(s.Trump, s.Rules().HandSize),
+			Expect: s.Hands[nextToDiscard].NumToDiscard(s.Trump, s.Rules().HandSize),
 	}
 }
 
@@ -122,3 +122,119 @@ func TestOmit(t *testing.T) {
 		}
 	}
 }
+
+func TestDiscardTooManyTrump(t *testing.T) {
+	// East holds eight trump and a club, more trump than fit in a Hand.
+	holding := func() *hand.Hand {
+		return &hand.Hand{
+			card.Card{card.Deuce, card.Hearts},
+			card.Card{card.Three, card.Hearts},
+			card.Card{card.Four, card.Hearts},
+			card.Card{card.Six, card.Hearts},
+			card.Card{card.Seven, card.Hearts},
+			card.Card{card.Eight, card.Hearts},
+			card.Card{card.Nine, card.Hearts},
+			card.Card{card.Ace, card.Hearts},
+			card.Card{card.Ace, card.Clubs},
+		}
+	}
+	for _, test := range []struct {
+		name    string
+		options []int
+		wantErr bool
+	}{
+		{"ClubAndLowTrump", []int{1, 2, 8}, false},
+		{"KeepsClub", []int{1, 2, 3}, true},
+		{"KeepsTooMany", []int{8}, true},
+	} {
+		s := state.State{
+			Dealer: seat.North,
+			Bids:   map[seat.Seat]bid.Bid{seat.South: bid.B8},
+			Trump:  card.Hearts,
+			Hands:  map[seat.Seat]*hand.Hand{seat.East: holding()},
+		}
+		_, _, err := discard(s, Message{Type: Discard, Seat: seat.East, Options: test.options})
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %t", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestDiscardRevealsNonWinnersTrump(t *testing.T) {
+	s := state.State{
+		Dealer: seat.North,
+		Bids:   map[seat.Seat]bid.Bid{seat.South: bid.B8},
+		Trump:  card.Hearts,
+		Hands: map[seat.Seat]*hand.Hand{
+			seat.East: &hand.Hand{
+				card.Card{card.Three, card.Hearts},
+				card.Card{card.Four, card.Hearts},
+				card.Card{card.Six, card.Hearts},
+				card.Card{card.Seven, card.Hearts},
+				card.Card{card.Eight, card.Hearts},
+				card.Card{card.Nine, card.Hearts},
+				card.Card{card.Ten, card.Hearts},
+				card.Card{card.Ace, card.Clubs},
+			},
+		},
+	}
+	got, _, err := discard(s, Message{Type: Discard, Seat: seat.East, Options: []int{0, 7}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []state.Reveal{{seat.East, card.Card{card.Three, card.Hearts}, true}}
+	for _, c := range *got.Hands[seat.East] {
+		want = append(want, state.Reveal{seat.East, c, false})
+	}
+	if !reflect.DeepEqual(got.Revealed, want) {
+		t.Errorf("want reveals %v, got %v", want, got.Revealed)
+	}
+}
+
+func TestDiscardRevealsWinnersTrump(t *testing.T) {
+	s := state.State{
+		Dealer: seat.North,
+		Bids:   map[seat.Seat]bid.Bid{seat.East: bid.B8},
+		Trump:  card.Hearts,
+		Hands: map[seat.Seat]*hand.Hand{
+			seat.East: &hand.Hand{
+				card.Card{card.Three, card.Hearts},
+				card.Card{card.Four, card.Hearts},
+				card.Card{card.Six, card.Hearts},
+				card.Card{card.Seven, card.Hearts},
+				card.Card{card.Eight, card.Hearts},
+				card.Card{card.Nine, card.Hearts},
+				card.Card{card.Ten, card.Hearts},
+				card.Card{card.Ace, card.Clubs},
+			},
+		},
+	}
+	got, _, err := discard(s, Message{Type: Discard, Seat: seat.East, Options: []int{0, 7}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []state.Reveal{{seat.East, card.Card{card.Three, card.Hearts}, true}}
+	for _, c := range *got.Hands[seat.East] {
+		want = append(want, state.Reveal{seat.East, c, false})
+	}
+	if !reflect.DeepEqual(got.Revealed, want) {
+		t.Errorf("want reveals %v, got %v", want, got.Revealed)
+	}
+
+	s.Hands[seat.East] = &hand.Hand{
+		card.Card{card.Three, card.Hearts},
+		card.Card{card.Four, card.Hearts},
+		card.Card{card.Six, card.Hearts},
+		card.Card{card.Seven, card.Hearts},
+		card.Card{card.Eight, card.Hearts},
+		card.Card{card.Nine, card.Hearts},
+		card.Card{card.Ace, card.Clubs},
+	}
+	got, _, err = discard(s, Message{Type: Discard, Seat: seat.East, Options: []int{6}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got.Revealed) != 0 {
+		t.Errorf("want no reveals for a non-trump discard, got %v", got.Revealed)
+	}
+}
@@ -0,0 +1,56 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	"dr2w.com/hf/model/deck"
+)
+
+// The categories of error NextState returns, wrapped in a *StepError. Test
+// for a category with errors.Is.
+var (
+	// ErrIllegal means the rules do not allow the selections made.
+	ErrIllegal = errors.New("selection breaks the rules")
+	// ErrWrongSeat means the Message came from a Seat which may not act.
+	ErrWrongSeat = errors.New("wrong seat")
+	// ErrRevoke means a card was played which does not follow the suit led
+	// when the Hand could follow it.
+	ErrRevoke = errors.New("revoke")
+	// ErrDiscardCount means the wrong number or kind of cards were
+	// discarded.
+	ErrDiscardCount = errors.New("invalid discard count")
+	// ErrDeckExhausted means more cards were to be dealt than the Deck
+	// holds.
+	ErrDeckExhausted = deck.ErrExhausted
+	// ErrInvariant means the State was not one the Message may be applied
+	// to, which points to a bug rather than a bad selection.
+	ErrInvariant = errors.New("internal invariant violated")
+)
+
+// StepError describes a failure to apply a Message to a State.
+type StepError struct {
+	Message Message
+	Err     error
+}
+
+// Error implements error.
+func (e *StepError) Error() string {
+	return fmt.Sprintf("unable to apply %s: %s", e.Message, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Illegal returns true iff err means that the selections made, or the Seat
+// which made them, were at fault rather than the State they were applied to.
+func Illegal(err error) bool {
+	for _, cause := range []error{ErrIllegal, ErrWrongSeat, ErrRevoke, ErrDiscardCount, ErrWrongCount, ErrNotOffered, ErrRepeated} {
+		if errors.Is(err, cause) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package action
+
+import (
+	"errors"
+	"testing"
+
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/model/trick"
+)
+
+// leading returns a State in which North has led the Seven of Diamonds, with
+// Hearts as trump, and East holds the Three of Clubs and Nine of Diamonds.
+func leading() state.State {
+	return state.State{
+		Hands: map[seat.Seat]*hand.Hand{
+			seat.North: &hand.Hand{c3c},
+			seat.East:  &hand.Hand{c3c, c9d},
+			seat.South: &hand.Hand{c9h, c7d},
+		},
+		Bids:   map[seat.Seat]bid.Bid{seat.North: bid.B7},
+		Trump:  card.Hearts,
+		Played: []trick.Trick{trick.New(c7d)},
+	}
+}
+
+var nextStateErrorTests = []struct {
+	name    string
+	state   state.State
+	message Message
+	want    error
+	illegal bool
+}{
+	{"Revoke", leading(), Message{Play, seat.East, []int{0}, 1}, ErrRevoke, true},
+	{"Out Of Turn", leading(), Message{Play, seat.South, []int{0}, 1}, ErrWrongSeat, true},
+	{"No Such Card", leading(), Message{Play, seat.East, []int{5}, 1}, ErrIllegal, true},
+	{"Two Cards", leading(), Message{Play, seat.East, []int{0, 1}, 1}, ErrWrongCount, true},
+	{"Fold Out Of Turn", leading(), Message{ThrowIn, seat.North, []int{Fold}, 1}, ErrWrongSeat, true},
+	{
+		"Bid Twice",
+		state.State{Bids: map[seat.Seat]bid.Bid{seat.North: bid.B7}},
+		Message{Bid, seat.North, []int{int(bid.B8)}, 1},
+		ErrWrongSeat,
+		true,
+	},
+	{
+		"Underbid",
+		state.State{Bids: map[seat.Seat]bid.Bid{seat.North: bid.B8}},
+		Message{Bid, seat.East, []int{int(bid.B7)}, 1},
+		ErrIllegal,
+		true,
+	},
+	{
+		"Deck Exhausted",
+		state.State{Dealer: seat.North, Deck: deck.Deck{c3c}},
+		Message{Deal, seat.North, []int{0}, 1},
+		ErrDeckExhausted,
+		false,
+	},
+	{"Unknown Type", state.State{}, Message{Type(100), seat.North, []int{0}, 1}, ErrInvariant, false},
+}
+
+func TestNextStateErrors(t *testing.T) {
+	for _, test := range nextStateErrorTests {
+		_, _, err := NextState(test.state, test.message)
+		var se *StepError
+		if !errors.As(err, &se) || !errors.Is(err, test.want) {
+			t.Errorf("%s: want a *StepError wrapping %q, got %v", test.name, test.want, err)
+			continue
+		}
+		if Illegal(err) != test.illegal {
+			t.Errorf("%s: Illegal(%s) = %v", test.name, err, !test.illegal)
+		}
+	}
+	if _, _, err := NextState(leading(), Message{Play, seat.East, []int{1}, 1}); err != nil {
+		t.Errorf("following suit: unexpected error: %s", err)
+	}
+}
@@ -16,10 +16,19 @@ import (
 func playCard(s state.State, st seat.Seat, c card.Card) ([]trick.Trick, error) {
 	last := s.LastPlayed()
 	if last.Full() || last.Empty() {
-		return append(s.Played, trick.Trick{Cards: map[seat.Seat]card.Card{st: c}, First: st}), nil
+		t := trick.Trick{Cards: map[seat.Seat]card.Card{st: c}, First: st}
+		for f, folded := range s.Folded {
+			if folded {
+				if t.Out == nil {
+					t.Out = make(map[seat.Seat]bool)
+				}
+				t.Out[f] = true
+			}
+		}
+		return append(s.Played, t), nil
 	}
-	if _, ok := last.Cards[st]; ok {
-		return []trick.Trick{}, fmt.Errorf("attempting to play %v:%v on Trick %v in State:\n%v", st, c, last, s)
+	if _, ok := last.Cards[st]; ok || last.Out[st] {
+		return []trick.Trick{}, fmt.Errorf("%w: %v has already played %v on Trick %v", ErrWrongSeat, st, c, last)
 	}
 	s.Played[len(s.Played)-1].Cards[st] = c
 	return s.Played, nil
@@ -40,8 +49,19 @@ func validCards(h *hand.Hand, trump card.Suit, trick trick.Trick) (options []int
 	return options
 }
 
+// contains returns true iff the given options include o.
+func contains(options []int, o int) bool {
+	for _, p := range options {
+		if p == o {
+			return true
+		}
+	}
+	return false
+}
+
 // next takes the current State of the game, waiting for a card to be played, and
 // returns the Message requesting that card or a Message indicating end of hand.
+// A Seat which is out of trump is first asked whether it wants to throw in.
 func next(s state.State) (Message, error) {
 	st, h := s.ToPlay()
 	if h == nil {
@@ -53,6 +73,14 @@ func next(s state.State) (Message, error) {
 	    Expect: 1,
         }, nil
 	}
+	if _, decided := s.Folded[st]; !decided && h.Length() > 0 && !h.HasSuit(s.Trump) {
+		return Message{
+			Type:    ThrowIn,
+			Seat:    st,
+			Options: []int{PlayOn, Fold},
+			Expect:  1,
+		}, nil
+	}
 	return Message{
 		Type:    Play,
 		Seat:    st,
@@ -86,15 +114,24 @@ func advanceEmptyHands(s state.State) (state.State, error) {
 func play(s state.State, m Message) (state.State, Message, error) {
     hand, ok := s.Hands[m.Seat]
     if !ok {
-        return state.State{}, Message{}, fmt.Errorf("Seat %v has no Hand (%v)", m.Seat, s)
+        return state.State{}, Message{}, fmt.Errorf("%w: %v has no Hand", ErrWrongSeat, m.Seat)
     }
+	if st, _ := s.ToPlay(); st != m.Seat {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v played when %v was to play", ErrWrongSeat, m.Seat, st)
+	}
 	index, err := m.Selection()
 	if err != nil {
 		return state.State{}, Message{}, err
 	}
+	if index < 0 || index >= hand.Length() {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v has no card %d", ErrIllegal, m.Seat, index)
+	}
+	if !contains(validCards(hand, s.Trump, s.LastPlayed()), index) {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v must follow %v", ErrRevoke, m.Seat, s.LastPlayed().SuitLead())
+	}
 	card, err := hand.Remove(index)
 	if err != nil {
-		return state.State{}, Message{}, err
+		return state.State{}, Message{}, fmt.Errorf("%w: %s", ErrInvariant, err)
 	}
 	s.Played, err = playCard(s, m.Seat, card)
 	if err != nil {
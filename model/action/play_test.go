@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"dr2w.com/hf/model/bid"
 	"dr2w.com/hf/model/card"
 	"dr2w.com/hf/model/hand"
 	"dr2w.com/hf/model/seat"
@@ -277,6 +278,7 @@ var playTests = []struct {
                 seat.North: &hand.Hand{c7d, c3c},
                 seat.East: &hand.Hand{c9h, c9d},
             },
+            Bids: map[seat.Seat]bid.Bid{seat.North: bid.B7},
             Trump: card.Diamonds,
         },
         Message{Play, seat.North, []int{0}, 1},
@@ -285,6 +287,7 @@ var playTests = []struct {
                 seat.North: &hand.Hand{c3c},
                 seat.East: &hand.Hand{c9h, c9d},
             },
+            Bids: map[seat.Seat]bid.Bid{seat.North: bid.B7},
             Trump: card.Diamonds,
             Played: []trick.Trick{trick.New(c7d)},
         },
@@ -295,7 +298,7 @@ var playTests = []struct {
         "Valid Later Play",
         state.State{
             Hands: map[seat.Seat]*hand.Hand{
-                seat.South: &hand.Hand{c7d, c3c},
+                seat.South: &hand.Hand{c9h, c3c},
                 seat.West: &hand.Hand{},
                 seat.North: &hand.Hand{c9h, c9d},
                 seat.East: &hand.Hand{c9h, c7d, c9d},
@@ -309,7 +312,7 @@ var playTests = []struct {
         Message{Play, seat.South, []int{1}, 1},
         state.State{
             Hands: map[seat.Seat]*hand.Hand{
-                seat.South: &hand.Hand{c7d},
+                seat.South: &hand.Hand{c9h},
                 seat.West: &hand.Hand{},
                 seat.North: &hand.Hand{c9h, c9d},
                 seat.East: &hand.Hand{c9h, c7d, c9d},
@@ -3,7 +3,6 @@ package action
 
 import (
     "fmt"
-    "math/rand"
 
 	"dr2w.com/hf/model/seat"
 	"dr2w.com/hf/model/card"
@@ -13,18 +12,29 @@ import (
 // redeal takes a State with bidding complete, trump decided, and non-winners
 // discarded down to only trump. It deals the three
 // non-bid-winning hands up to handSize and deals the rest of the deck to the
-// bid-winner.
+// bid-winner. A non-winner holding too many trump must discard some of them,
+// exposing its whole trump holding to the table. If the deck runs out, the
+// last non-winners to be dealt to play short.
 func redeal(s state.State, _ Message) (state.State, Message, error) {
     winner, _ := s.WinningBid()
+    size := s.Rules().HandSize
     st := s.Dealer.Next()
+    s.Kept = make(map[seat.Seat]int)
     for i := 0; i < len(seat.Order); i++ {
         if st == winner {
 	    st = st.Next()
             continue
         }
+        s.Kept[st] = s.Hands[st].Length()
+        if s.Kept[st] > size {
+            s.Kept[st] = size
+        }
 	// TODO(drw): Fix now that we discard non-trump before this.
-	toDeal := -s.Hands[st].ExtraCards()
-	fmt.Printf("ToDeal: %d", toDeal)
+	toDeal := -s.Hands[st].ExtraCards(size)
+        if toDeal > len(s.Deck) {
+            // The deck has run out; the last Seats play short.
+            toDeal = len(s.Deck)
+        }
         if toDeal > 0 {
             cards, err := s.Deck.Deal(toDeal)
             if err != nil {
@@ -32,15 +42,20 @@ func redeal(s state.State, _ Message) (state.State, Message, error) {
             }
             s.Hands[st].Add(cards...)
         } else if toDeal < 0 {
-	    for s.Hands[st].ExtraCards() > 0 {
-            	d := s.Hands[st].Discards(s.Trump)
-            	if len(d) < s.Hands[st].ExtraCards() {
-                	return state.State{}, Message{}, fmt.Errorf("unable to discard/redeal from hand %v with trump %v", s.Hands[st], s.Trump)
+	    for s.Hands[st].ExtraCards(size) > 0 {
+            	d := s.Hands[st].Discards(s.Trump, size)
+            	if len(d) < s.Hands[st].ExtraCards(size) {
+                	return state.State{}, Message{}, fmt.Errorf("%w: unable to discard down from hand %v with trump %v", ErrInvariant, s.Hands[st], s.Trump)
                 }
                 // TODO(drw): make this selection more reasonable
-                // TODO(drw): add s.Reveal(card) for this and for bid winner reveal
-                s.Hands[st].Remove(d[rand.Intn(len(d))])
+                c, err := s.Hands[st].Remove(d[s.Rng().Intn(len(d))])
+                if err != nil {
+                    return state.State{}, Message{}, fmt.Errorf("%w: %s", ErrInvariant, err)
+                }
+                s.Discard(st, c)
+                s.RevealDiscarded(st, c)
 	    }
+	    s.RevealHeld(st, *s.Hands[st]...)
         }
         st = st.Next()
     }
@@ -56,8 +71,8 @@ func redeal(s state.State, _ Message) (state.State, Message, error) {
 	r := Message{
 		Type:    Discard,
 		Seat:    winner,
-		Options: s.Hands[winner].Discards(s.Trump),
-		Expect: s.Hands[winner].ExtraCards(),
+		Options: s.Hands[winner].Discards(s.Trump, size),
+		Expect: s.Hands[winner].ExtraCards(size),
 	}
 	return s, r, nil
 }
@@ -1,11 +1,16 @@
 package action
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 
+	"dr2w.com/hf/model/bid"
 	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
 	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
 )
 
 var discardsTests = []struct {
@@ -50,8 +55,85 @@ var discardsTests = []struct {
 
 func TestDiscards(t *testing.T) {
     for _, test := range discardsTests {
-        if got := test.hand.Discards(test.trump); !reflect.DeepEqual(got, test.want) {
+        if got := test.hand.Discards(test.trump, 6); !reflect.DeepEqual(got, test.want) {
             t.Errorf("%s: got %v, want %v", test.name, got, test.want)
         }
     }
 }
+
+func TestRedealPlaysShort(t *testing.T) {
+	s := state.State{
+		Dealer: seat.North,
+		Bids:   map[seat.Seat]bid.Bid{seat.East: bid.B8},
+		Trump:  card.Hearts,
+		Deck:   deck.New()[:10],
+		Hands: map[seat.Seat]*hand.Hand{
+			seat.North: &hand.Hand{},
+			seat.East:  &hand.Hand{},
+			seat.South: &hand.Hand{},
+			seat.West:  &hand.Hand{},
+		},
+	}
+	got, _, err := redeal(s, Message{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for st, want := range map[seat.Seat]int{seat.South: 6, seat.West: 4, seat.North: 0, seat.East: 0} {
+		if n := got.Hands[st].Length(); n != want {
+			t.Errorf("%s was dealt %d cards, want %d", st, n, want)
+		}
+	}
+}
+
+func TestRedealRevealsForcedDiscard(t *testing.T) {
+	sixTrump := func() *hand.Hand {
+		return &hand.Hand{
+			card.Card{card.Three, card.Spades},
+			card.Card{card.Four, card.Spades},
+			card.Card{card.Six, card.Spades},
+			card.Card{card.Seven, card.Spades},
+			card.Card{card.Eight, card.Spades},
+			card.Card{card.Nine, card.Spades},
+		}
+	}
+	south := &hand.Hand{
+		card.Card{card.Three, card.Hearts},
+		card.Card{card.Four, card.Hearts},
+		card.Card{card.Six, card.Hearts},
+		card.Card{card.Seven, card.Hearts},
+		card.Card{card.Eight, card.Hearts},
+		card.Card{card.Nine, card.Hearts},
+		card.Card{card.Ten, card.Hearts},
+	}
+	s := state.State{
+		Dealer: seat.North,
+		Bids:   map[seat.Seat]bid.Bid{seat.East: bid.B8},
+		Trump:  card.Hearts,
+		Hands: map[seat.Seat]*hand.Hand{
+			seat.North: sixTrump(),
+			seat.East:  &hand.Hand{},
+			seat.South: south,
+			seat.West:  sixTrump(),
+		},
+		Rand: rand.New(rand.NewSource(0)),
+	}
+	got, _, err := redeal(s, Message{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got.Revealed) != 7 {
+		t.Fatalf("want 7 reveals, got %v", got.Revealed)
+	}
+	if got.Kept[seat.South] != 6 || got.Kept[seat.North] != 6 || got.Kept[seat.East] != 0 {
+		t.Errorf("want six trump kept by each non-winner, got %v", got.Kept)
+	}
+	discarded := got.Revealed[0]
+	if discarded.Seat != seat.South || !discarded.Discarded || card.Set(*got.Hands[seat.South]).Contains(discarded.Card) {
+		t.Errorf("want South's forced discard revealed first, got %v", discarded)
+	}
+	for _, r := range got.Revealed[1:] {
+		if r.Seat != seat.South || r.Discarded || !card.Set(*got.Hands[seat.South]).Contains(r.Card) {
+			t.Errorf("want South's remaining trump revealed as held, got %v", r)
+		}
+	}
+}
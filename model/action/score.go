@@ -8,35 +8,71 @@ import (
     "dr2w.com/hf/model/card"
 )
 
-func points(tricks []trick.Trick, trump card.Suit) map[seat.Seat]int {
-    p := make(map[seat.Seat]int)
+// captures returns what the winner of each of the given Tricks took, with
+// the cards in the order they were played. The empty cards played from empty
+// Hands are left out, as are Tricks on which no card was played at all.
+func captures(tricks []trick.Trick, trump card.Suit) []state.Capture {
+    var cs []state.Capture
     for _, t := range tricks {
+        var cards card.Set
+        st := t.First
+        for range seat.Order {
+            if c, ok := t.Cards[st]; ok && c != (card.Card{}) {
+                cards = append(cards, c)
+            }
+            st = st.Next()
+        }
+        if len(cards) == 0 {
+            continue
+        }
         s, _ := t.Winner(trump)
-        points := t.Points(trump)
-        p[s] += points
-        p[s.Partner()] += points
+        cs = append(cs, state.Capture{Winner: s, Cards: cards, Points: t.Points(trump)})
+    }
+    return cs
+}
+
+// points returns the points each Team took on the given Captures.
+func points(cs []state.Capture) map[seat.Team]int {
+    p := make(map[seat.Team]int)
+    for _, c := range cs {
+        p[c.Winner.Team()] += c.Points
     }
     return p
 }
 
-func resolve(s seat.Seat, b bid.Bid, points map[seat.Seat]int) map[seat.Seat]int {
-    scores := make(map[seat.Seat]int)
-    nonPts := points[s.Next()]
-    bidScore := b.Score(points[s])
-    scores[s], scores[s.Partner()] = bidScore, bidScore
-    scores[s.Next()], scores[s.Partner().Next()] = nonPts, nonPts
-    return scores
+// resolve returns the score of each Team for a round in which the given
+// Seat won the bidding with the given Bid: the bidding Team scores according
+// to its Bid, and the other Team scores the points it took.
+func resolve(s seat.Seat, b bid.Bid, points map[seat.Team]int) map[seat.Team]int {
+    bidding := s.Team()
+    return map[seat.Team]int{
+        bidding:             b.Score(points[bidding]),
+        bidding.Opponents(): points[bidding.Opponents()],
+    }
 }
 
-// score increments the scores appropriately and resets the rest of the
-// state for the next hand.
-func score(s state.State, m Message) (state.State, Message, error) {
+// result returns the RoundResult of a State whose round has been played out.
+func result(s state.State) state.RoundResult {
     bs, b := s.WinningBid()
-    p := points(s.Played, s.Trump)
-    //fmt.Printf("Points => %v\n", p)
-    scores := resolve(bs, b, p)
-    //fmt.Printf("Scores => %v\n", scores)
-    next := s.NextRound(scores)
+    cs := captures(s.Played, s.Trump)
+    p := points(cs)
+    taken := p[bs.Team()]
+    return state.RoundResult{
+        Bidder: bs,
+        Bid:    b,
+        Trump:  s.Trump,
+        Points: p,
+        Made:   taken >= bid.Points[b],
+        Margin: taken - bid.Points[b],
+        Delta:  resolve(bs, b, p),
+        Tricks: cs,
+    }
+}
+
+// score records the result of the round, increments the scores
+// appropriately and resets the rest of the state for the next hand.
+func score(s state.State, m Message) (state.State, Message, error) {
+    next := s.NextRound(result(s))
     return next,
 	   Message{Deal, next.Dealer, []int{0}, 1}, nil
 }
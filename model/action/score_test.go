@@ -0,0 +1,47 @@
+package action
+
+import (
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/seat"
+)
+
+var resolveTests = []struct {
+	name   string
+	seat   seat.Seat
+	bid    bid.Bid
+	points map[seat.Team]int
+	want   map[seat.Team]int
+}{
+	{
+		"Made",
+		seat.North,
+		bid.B8,
+		map[seat.Team]int{seat.NorthSouth: 10, seat.EastWest: 5},
+		map[seat.Team]int{seat.NorthSouth: 10, seat.EastWest: 5},
+	},
+	{
+		"Set",
+		seat.West,
+		bid.B9,
+		map[seat.Team]int{seat.NorthSouth: 7, seat.EastWest: 8},
+		map[seat.Team]int{seat.NorthSouth: 7, seat.EastWest: -9},
+	},
+	{
+		"Nothing Taken",
+		seat.East,
+		bid.B7,
+		map[seat.Team]int{seat.NorthSouth: 15},
+		map[seat.Team]int{seat.NorthSouth: 15, seat.EastWest: -7},
+	},
+}
+
+func TestResolve(t *testing.T) {
+	for _, test := range resolveTests {
+		if got := resolve(test.seat, test.bid, test.points); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: want %v, got %v", test.name, test.want, got)
+		}
+	}
+}
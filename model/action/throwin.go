@@ -0,0 +1,68 @@
+package action
+
+import (
+	"fmt"
+
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+// The Options offered by a ThrowIn Message.
+const (
+	// PlayOn keeps the Hand in play for the rest of the round.
+	PlayOn = 0
+	// Fold throws in the rest of the Hand.
+	Fold = 1
+)
+
+// throwIn takes a State in which the Seat in the Message is out of trump and
+// is next to play, and the selected decision. If the Seat folds, its Hand is thrown in and it sits
+// out every remaining Trick of the round. Returns the resulting State and a
+// request for the next play.
+func throwIn(s state.State, m Message) (state.State, Message, error) {
+	sel, err := m.Selection()
+	if err != nil {
+		return state.State{}, Message{}, err
+	}
+	h, ok := s.Hands[m.Seat]
+	if !ok {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v has no Hand", ErrWrongSeat, m.Seat)
+	}
+	if sel != PlayOn && sel != Fold {
+		return state.State{}, Message{}, fmt.Errorf("%w: throw in selection %d", ErrIllegal, sel)
+	}
+	if sel == Fold && h.HasSuit(s.Trump) {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v may not throw in while holding trump", ErrIllegal, m.Seat)
+	}
+	if _, decided := s.Folded[m.Seat]; decided {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v has already decided whether to throw in", ErrWrongSeat, m.Seat)
+	}
+	// next never fails.
+	if pending, _ := next(s); pending.Type != ThrowIn || pending.Seat != m.Seat {
+		return state.State{}, Message{}, fmt.Errorf("%w: %v threw in when %v was to %v", ErrWrongSeat, m.Seat, pending.Seat, pending.Type)
+	}
+	if s.Folded == nil {
+		s.Folded = make(map[seat.Seat]bool)
+	}
+	s.Folded[m.Seat] = sel == Fold
+	if sel == Fold {
+		s.Discard(m.Seat, *h...)
+		s.Hands[m.Seat] = &hand.Hand{}
+		if last := s.LastPlayed(); !last.Full() && !last.Empty() {
+			if last.Out == nil {
+				s.Played[len(s.Played)-1].Out = make(map[seat.Seat]bool)
+			}
+			s.Played[len(s.Played)-1].Out[m.Seat] = true
+		}
+	}
+	s, err = advanceEmptyHands(s)
+	if err != nil {
+		return state.State{}, Message{}, err
+	}
+	msg, err := next(s)
+	if err != nil {
+		return state.State{}, Message{}, err
+	}
+	return s, msg, nil
+}
@@ -0,0 +1,207 @@
+package action
+
+import (
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+	"dr2w.com/hf/model/trick"
+)
+
+var throwInTests = []struct {
+	name     string
+	inState  state.State
+	inMsg    Message
+	outState state.State
+	outMsg   Message
+	err      bool
+}{
+	{
+		"Bad Selection",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{seat.East: &hand.Hand{c3c}},
+			Trump: card.Diamonds,
+		},
+		Message{ThrowIn, seat.East, []int{2}, 1},
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Holding Trump",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{seat.East: &hand.Hand{c7d}},
+			Trump: card.Diamonds,
+		},
+		Message{ThrowIn, seat.East, []int{Fold}, 1},
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Play On",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{c3c, c9h},
+				seat.South: &hand.Hand{c7d},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c9d)},
+		},
+		Message{ThrowIn, seat.East, []int{PlayOn}, 1},
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{c3c, c9h},
+				seat.South: &hand.Hand{c7d},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c9d)},
+			Folded: map[seat.Seat]bool{seat.East: false},
+		},
+		Message{Play, seat.East, []int{0, 1}, 1},
+		false,
+	},
+	{
+		"Fold Mid Trick",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{c3c, c9h},
+				seat.South: &hand.Hand{c7d},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c9d)},
+		},
+		Message{ThrowIn, seat.East, []int{Fold}, 1},
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{},
+				seat.South: &hand.Hand{c7d},
+			},
+			Trump: card.Diamonds,
+			Played: []trick.Trick{{
+				Cards: map[seat.Seat]card.Card{seat.North: c9d},
+				First: seat.North,
+				Out:   map[seat.Seat]bool{seat.East: true},
+			}},
+			Folded:    map[seat.Seat]bool{seat.East: true},
+			Discarded: card.Set{c3c, c9h},
+			Discards:  map[seat.Seat]card.Set{seat.East: {c3c, c9h}},
+		},
+		Message{Play, seat.South, []int{0}, 1},
+		false,
+	},
+	{
+		"No Hand",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.North: &hand.Hand{c3c},
+				seat.East:  &hand.Hand{c9h},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c3c, c7d, c9d, c9h)},
+		},
+		Message{ThrowIn, seat.South, []int{Fold}, 1},
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Out Of Turn",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{c3c},
+				seat.South: &hand.Hand{c9h},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c9d)},
+		},
+		Message{ThrowIn, seat.South, []int{Fold}, 1},
+		state.State{},
+		Message{},
+		true,
+	},
+	{
+		"Already Decided",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{c3c, c9h},
+				seat.South: &hand.Hand{c7d},
+			},
+			Trump:  card.Diamonds,
+			Played: []trick.Trick{trick.New(c9d)},
+			Folded: map[seat.Seat]bool{seat.East: false},
+		},
+		Message{ThrowIn, seat.East, []int{Fold}, 1},
+		state.State{},
+		Message{},
+		true,
+	},
+}
+
+func TestThrowIn(t *testing.T) {
+	for _, test := range throwInTests {
+		outState, outMsg, err := throwIn(test.inState, test.inMsg)
+		if !reflect.DeepEqual(outState, test.outState) {
+			t.Errorf("%s: want %v, got %v", test.name, test.outState, outState)
+		}
+		if !reflect.DeepEqual(outMsg, test.outMsg) {
+			t.Errorf("%s: want %v, got %v", test.name, test.outMsg, outMsg)
+		}
+		if err != nil && !test.err {
+			t.Errorf("%s: unexpected error - %s", test.name, err)
+		}
+		if err == nil && test.err {
+			t.Errorf("%s: expected error, got none", test.name)
+		}
+	}
+}
+
+var throwInNextTests = []struct {
+	name  string
+	state state.State
+	want  Message
+}{
+	{
+		"Out Of Trump",
+		state.State{
+			Hands:  map[seat.Seat]*hand.Hand{seat.East: &hand.Hand{c3c, c9h}},
+			Played: []trick.Trick{trick.New(c9d)},
+			Trump:  card.Diamonds,
+		},
+		Message{ThrowIn, seat.East, []int{PlayOn, Fold}, 1},
+	},
+	{
+		"Lead Passes From Folded Winner",
+		state.State{
+			Hands: map[seat.Seat]*hand.Hand{
+				seat.East:  &hand.Hand{},
+				seat.South: &hand.Hand{c7d},
+			},
+			Played: []trick.Trick{trick.New(c3c, c9d, c7d, c9h)},
+			Trump:  card.Diamonds,
+			Folded: map[seat.Seat]bool{seat.East: true},
+		},
+		Message{Play, seat.South, []int{0}, 1},
+	},
+	{
+		"Everyone Folded",
+		state.State{
+			Hands:  map[seat.Seat]*hand.Hand{seat.East: &hand.Hand{}},
+			Played: []trick.Trick{trick.New(c3c, c9d, c7d, c9h)},
+			Trump:  card.Diamonds,
+			Folded: map[seat.Seat]bool{seat.North: true, seat.East: true, seat.South: true, seat.West: true},
+		},
+		Message{Score, seat.None, []int{0}, 1},
+	},
+}
+
+func TestThrowInNext(t *testing.T) {
+	for _, test := range throwInNextTests {
+		if got, _ := next(test.state); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: want %v, got %v", test.name, test.want, got)
+		}
+	}
+}
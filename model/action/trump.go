@@ -25,7 +25,7 @@ func trump(s state.State, m Message) (state.State, Message, error) {
 	return s, Message{
 		Type:    Discard,
 		Seat:    firstToDiscard,
-		Options: s.Hands[firstToDiscard].Discards(s.Trump),
-		Expect: s.Hands[firstToDiscard].NumToDiscard(s.Trump),
+		Options: s.Hands[firstToDiscard].Discards(s.Trump, s.Rules().HandSize),
+		Expect: s.Hands[firstToDiscard].NumToDiscard(s.Trump, s.Rules().HandSize),
 	}, nil
 }
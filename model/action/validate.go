@@ -0,0 +1,64 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The reasons a response may be rejected by Validate. A *ResponseError
+// wraps one of them, or one of the errors NextState returns, so that callers
+// may test for a reason with errors.Is.
+var (
+	// ErrWrongCount means the response did not hold Expect selections.
+	ErrWrongCount = errors.New("wrong number of selections")
+	// ErrNotOffered means a selection was not one of the Options.
+	ErrNotOffered = errors.New("selection not offered")
+	// ErrRepeated means a selection was made more than once.
+	ErrRepeated = errors.New("selection repeated")
+)
+
+// ResponseError describes a response which does not answer the Message it
+// was given in reply to.
+type ResponseError struct {
+	Request  Message
+	Response []int
+	Err      error
+}
+
+// Error implements error.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("invalid response %v from %s to %s: %s", e.Response, e.Request.Seat, e.Request, e.Err)
+}
+
+// Unwrap returns the reason the response was rejected.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Validate returns a *ResponseError if the given response does not answer
+// m, which requires exactly Expect distinct selections from its Options. It
+// does not check that the rules allow the selections to be made together;
+// NextState does that.
+func (m Message) Validate(response []int) error {
+	reject := func(err error) error {
+		return &ResponseError{Request: m, Response: response, Err: err}
+	}
+	if len(response) != m.Expect {
+		return reject(fmt.Errorf("%w: expected %d, got %d", ErrWrongCount, m.Expect, len(response)))
+	}
+	offered := make(map[int]bool, len(m.Options))
+	for _, o := range m.Options {
+		offered[o] = true
+	}
+	chosen := make(map[int]bool, len(response))
+	for _, r := range response {
+		if !offered[r] {
+			return reject(fmt.Errorf("%w: %d", ErrNotOffered, r))
+		}
+		if chosen[r] {
+			return reject(fmt.Errorf("%w: %d", ErrRepeated, r))
+		}
+		chosen[r] = true
+	}
+	return nil
+}
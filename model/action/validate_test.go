@@ -0,0 +1,37 @@
+package action
+
+import (
+	"errors"
+	"testing"
+
+	"dr2w.com/hf/model/seat"
+)
+
+var validateTests = []struct {
+	name     string
+	response []int
+	want     error
+}{
+	{"Valid", []int{2, 5}, nil},
+	{"Empty", []int{}, ErrWrongCount},
+	{"Too Many", []int{0, 2, 5}, ErrWrongCount},
+	{"Not Offered", []int{2, 3}, ErrNotOffered},
+	{"Repeated", []int{5, 5}, ErrRepeated},
+}
+
+func TestValidate(t *testing.T) {
+	m := Message{Type: Discard, Seat: seat.East, Options: []int{0, 2, 5, 7}, Expect: 2}
+	for _, test := range validateTests {
+		err := m.Validate(test.response)
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			}
+			continue
+		}
+		var re *ResponseError
+		if !errors.As(err, &re) || !errors.Is(err, test.want) {
+			t.Errorf("%s: want a *ResponseError wrapping %q, got %v", test.name, test.want, err)
+		}
+	}
+}
@@ -1,12 +1,33 @@
 // Package bid models players' bids
 package bid
 
+import "fmt"
+
 type Bid int
 
 func (b Bid) String() string {
     return Names[b]
 }
 
+// MarshalText implements encoding.TextMarshaler using the Bid's name.
+func (b Bid) MarshalText() ([]byte, error) {
+    if _, ok := Names[b]; !ok {
+        return nil, fmt.Errorf("unable to marshal unknown bid %d", int(b))
+    }
+    return []byte(Names[b]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Bid names.
+func (b *Bid) UnmarshalText(text []byte) error {
+    for v, name := range Names {
+        if name == string(text) {
+            *b = v
+            return nil
+        }
+    }
+    return fmt.Errorf("unknown bid %q", text)
+}
+
 // Score returns the score gained by taking the given points during a hand.
 func (b Bid) Score(points int) int {
     if points < Points[b] {
@@ -0,0 +1,23 @@
+package bid
+
+import (
+	"testing"
+)
+
+func TestBidText(t *testing.T) {
+	for _, b := range Values {
+		text, err := b.MarshalText()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", b, err)
+			continue
+		}
+		var got Bid
+		if err := got.UnmarshalText(text); err != nil || got != b {
+			t.Errorf("%s: round trip through %q gave %s (%v)", b, text, got, err)
+		}
+	}
+	var b Bid
+	if err := b.UnmarshalText([]byte("16")); err == nil {
+		t.Errorf("expected an error unmarshalling an unknown bid")
+	}
+}
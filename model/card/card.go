@@ -4,6 +4,7 @@ package card
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type Suit int
@@ -12,6 +13,25 @@ func (s Suit) String() string {
 	return suitNames[s]
 }
 
+// MarshalText implements encoding.TextMarshaler using the Suit's name.
+func (s Suit) MarshalText() ([]byte, error) {
+	if _, ok := suitNames[s]; !ok {
+		return nil, fmt.Errorf("unable to marshal unknown suit %d", int(s))
+	}
+	return []byte(suitNames[s]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Suit names.
+func (s *Suit) UnmarshalText(text []byte) error {
+	for suit, name := range suitNames {
+		if name == string(text) {
+			*s = suit
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown suit %q", text)
+}
+
 const (
 	NoSuit Suit = iota
 	Diamonds
@@ -26,6 +46,25 @@ func (v Value) String() string {
 	return valueNames[v]
 }
 
+// MarshalText implements encoding.TextMarshaler using the Value's name.
+func (v Value) MarshalText() ([]byte, error) {
+	if _, ok := valueNames[v]; !ok {
+		return nil, fmt.Errorf("unable to marshal unknown value %d", int(v))
+	}
+	return []byte(valueNames[v]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Value names.
+func (v *Value) UnmarshalText(text []byte) error {
+	for value, name := range valueNames {
+		if name == string(text) {
+			*v = value
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown value %q", text)
+}
+
 const (
 	NoValue Value = iota
 	Deuce
@@ -108,6 +147,67 @@ func CardsFromShorthand(suit Suit, s string) []Card {
 	return cards
 }
 
+// Shorthand returns the single character shorthand for the Value, the inverse
+// of ValuesFromShorthand.
+func (v Value) Shorthand() string {
+	for r, value := range shorthandValues {
+		if value == v {
+			return string(r)
+		}
+	}
+	return "X"
+}
+
+// Shorthand returns a compact representation of the Card: its Value
+// shorthand followed by its Suit, e.g. "TH" or "jX" for an unsuited Joker.
+func (c Card) Shorthand() string {
+	return c.Value.Shorthand() + c.Suit.String()
+}
+
+// FromShorthand parses a single Card in the format produced by Card.Shorthand.
+func FromShorthand(s string) (Card, error) {
+	runes := []rune(s)
+	if len(runes) != 2 {
+		return Card{}, fmt.Errorf("invalid card shorthand %q", s)
+	}
+	v, ok := shorthandValues[runes[0]]
+	if runes[0] == 'X' {
+		v, ok = NoValue, true
+	}
+	if !ok {
+		return Card{}, fmt.Errorf("invalid value in card shorthand %q", s)
+	}
+	for suit, name := range suitNames {
+		if name == string(runes[1]) {
+			return Card{v, suit}, nil
+		}
+	}
+	return Card{}, fmt.Errorf("invalid suit in card shorthand %q", s)
+}
+
+// HandOrder is the order in which suits appear in hand shorthand.
+var HandOrder = []Suit{Spades, Hearts, Diamonds, Clubs}
+
+// Shorthand returns the Set in the comma-separated hand shorthand used by the
+// examples in this package (Spades, Hearts, Diamonds, Clubs), e.g.
+// "AKQ,T984,,j2". An unsuited Joker is listed with the Spades.
+func (s Set) Shorthand() string {
+	groups := make([]string, len(HandOrder))
+	for i, suit := range HandOrder {
+		var cards Set
+		for _, c := range s {
+			if c.Suit == suit || c.Suit == NoSuit && suit == HandOrder[0] {
+				cards = append(cards, c)
+			}
+		}
+		cards.Sort()
+		for _, c := range cards {
+			groups[i] += c.Value.Shorthand()
+		}
+	}
+	return strings.Join(groups, ",")
+}
+
 var suitNames = map[Suit]string{
 	NoSuit:   "X",
 	Diamonds: "D",
@@ -126,6 +226,29 @@ func (c Card) String() string {
 	return fmt.Sprintf("[%s of %s]", c.Value, c.Suit)
 }
 
+// MarshalText implements encoding.TextMarshaler using the Card's shorthand,
+// so Cards are encoded as compact strings such as "TH" (or "XX" for the
+// empty Card).
+func (c Card) MarshalText() ([]byte, error) {
+	if _, ok := valueNames[c.Value]; !ok {
+		return nil, fmt.Errorf("unable to marshal card with unknown value %d", int(c.Value))
+	}
+	if _, ok := suitNames[c.Suit]; !ok {
+		return nil, fmt.Errorf("unable to marshal card with unknown suit %d", int(c.Suit))
+	}
+	return []byte(c.Shorthand()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Card shorthand.
+func (c *Card) UnmarshalText(text []byte) error {
+	parsed, err := FromShorthand(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
 // Beats returns true if this card outranks the given card given the trump and lead suits.
 func (c Card) Beats(o Card, trump Suit, lead Suit) bool {
 	if c.Suit == o.Suit {
@@ -42,8 +42,6 @@ var valueMap = map[rune]Value{
 	'2': Deuce,
 }
 
-var suitOrder = []Suit{Spades, Hearts, Diamonds, Clubs}
-
 // hand converts the given string into a card.Set.
 // It looks for comma-separated character strings, where
 // each character represents a card and the suits are
@@ -58,7 +56,7 @@ func hand(s string) Set {
 	cards := Set{}
 	for i, values := range strings.Split(s, ",") {
 		for _, v := range values {
-			suit := suitOrder[i]
+			suit := HandOrder[i]
 			if v == 'j' {
 				suit = NoSuit
 			}
@@ -0,0 +1,70 @@
+package card
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCardText(t *testing.T) {
+	cards := Set{{}, {Joker, NoSuit}, {Joker, Hearts}, {OffFive, Hearts}}
+	for _, suit := range Suits {
+		for _, value := range SuitedValues {
+			cards = append(cards, Card{value, suit})
+		}
+	}
+	for _, c := range cards {
+		text, err := c.MarshalText()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c, err)
+			continue
+		}
+		var got Card
+		if err := got.UnmarshalText(text); err != nil || got != c {
+			t.Errorf("%s: round trip through %q gave %s (%v)", c, text, got, err)
+		}
+	}
+}
+
+var cardTextErrorTests = []string{"", "A", "AHH", "ZH", "AZ"}
+
+func TestCardTextErrors(t *testing.T) {
+	for _, test := range cardTextErrorTests {
+		var c Card
+		if err := c.UnmarshalText([]byte(test)); err == nil {
+			t.Errorf("%q: expected an error, got %s", test, c)
+		}
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	want := Set{{Ace, Spades}, {Joker, Clubs}, {Ten, Diamonds}}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != `["AS","jC","TD"]` {
+		t.Errorf("got %s, want compact card strings", b)
+	}
+	var got Set
+	if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip gave %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestSuitAndValueText(t *testing.T) {
+	for _, s := range append([]Suit{NoSuit}, Suits...) {
+		text, _ := s.MarshalText()
+		var got Suit
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("%s: round trip through %q gave %s (%v)", s, text, got, err)
+		}
+	}
+	for _, v := range append([]Value{NoValue}, Values...) {
+		text, _ := v.MarshalText()
+		var got Value
+		if err := got.UnmarshalText(text); err != nil || got != v {
+			t.Errorf("%s: round trip through %q gave %s (%v)", v, text, got, err)
+		}
+	}
+}
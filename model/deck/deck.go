@@ -2,6 +2,7 @@
 package deck
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -11,6 +12,9 @@ import (
 
 type Deck card.Set
 
+// ErrExhausted is returned when more cards are dealt than a Deck holds.
+var ErrExhausted = errors.New("deck exhausted")
+
 // String returns a string representation of the deck - a single line of comma-separated cards.
 func (d Deck) String() string {
 	s := make([]string, len(d))
@@ -23,13 +27,19 @@ func (d Deck) String() string {
 // Shuffle takes a deck and reorders the card in a semi-random fashion. The integer parameter affects
 // how thorough the shuffling is.
 func (d Deck) Shuffle(n int) {
+	d.ShuffleWith(rand.New(rand.NewSource(rand.Int63())), n)
+}
+
+// ShuffleWith behaves like Shuffle but draws from the given source of randomness, so that
+// the resulting order is reproducible for a given seed.
+func (d Deck) ShuffleWith(r *rand.Rand, n int) {
 	size := len(d)
 	if size == 0 {
 		return
 	}
 	for i := 0; i < n; i++ {
-		a := rand.Intn(size)
-		b := rand.Intn(size)
+		a := r.Intn(size)
+		b := r.Intn(size)
 		d[a], d[b] = d[b], d[a]
 	}
 }
@@ -41,7 +51,7 @@ func (d *Deck) Deal(n int) (card.Set, error) {
         return card.Set([]card.Card{}), nil
     }
 	if n > len(*d) || n < 0 {
-		return card.Set{}, fmt.Errorf("Cannot deal %d cards from a deck with only %d cards.", n, len(*d))
+		return card.Set{}, fmt.Errorf("%w: cannot deal %d cards from a deck with only %d cards", ErrExhausted, n, len(*d))
 	}
 	ret := (*d)[:n]
 	*d = (*d)[n:]
@@ -66,3 +76,11 @@ func Shuffled() Deck {
 	d.Shuffle(len(d))
 	return d
 }
+
+// ShuffledWith returns a new Deck shuffled once for every card in the deck using the
+// given source of randomness.
+func ShuffledWith(r *rand.Rand) Deck {
+	d := New()
+	d.ShuffleWith(r, len(d))
+	return d
+}
@@ -1,6 +1,7 @@
 package deck
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -88,11 +89,11 @@ func TestDeal(t *testing.T) {
 		case err == nil && test.err:
 			t.Errorf("%s: Expected an error, but got none.", test.name)
 		case err != nil && !test.err:
-			t.Errorf("%s: Unexpected error (%s)", err.Error())
+			t.Errorf("%s: Unexpected error (%s)", test.name, err.Error())
 		case len(test.deck) != test.left:
-			t.Errorf("%s: Want %d remaining cards, got %d", test.left, len(test.deck))
+			t.Errorf("%s: Want %d remaining cards, got %d", test.name, test.left, len(test.deck))
 		case !reflect.DeepEqual(dealt, test.dealt):
-			t.Errorf("%s: Want %v, got %v", dealt, test.dealt)
+			t.Errorf("%s: Want %v, got %v", test.name, test.dealt, dealt)
 		}
 	}
 }
@@ -131,3 +132,16 @@ func TestShuffle(t *testing.T) {
 		}
 	}
 }
+
+func TestShuffledWith(t *testing.T) {
+	for _, seed := range []int64{0, 1, 42} {
+		a := ShuffledWith(rand.New(rand.NewSource(seed)))
+		b := ShuffledWith(rand.New(rand.NewSource(seed)))
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("Seed %d: shuffles differ:\n%v\n%v", seed, a, b)
+		}
+		if reflect.DeepEqual(a, New()) {
+			t.Errorf("Seed %d: deck wasn't shuffled: %v", seed, a)
+		}
+	}
+}
@@ -2,28 +2,27 @@
 package hand
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"dr2w.com/hf/model/card"
 )
 
+// ErrNoCard is returned when a Hand is asked for a card it does not hold.
+var ErrNoCard = errors.New("no such card in hand")
+
 // Hand represents a set of cards to be owned and played by a player.
 type Hand card.Set
 
-// Constant Hand Size.
-func (h *Hand) MaxSize() int {
-	return 6
-}
-
 // Returns the card at the given position within the hand.
 func (h *Hand) Get(i int) card.Card {
 	return (*h)[i]
 }
 
-// Returns hand length minus max hand size. Can be positive or negative.
-func (h *Hand) ExtraCards() int {
-	return h.Length() - h.MaxSize()
+// Returns hand length minus the given hand size. Can be positive or negative.
+func (h *Hand) ExtraCards(size int) int {
+	return h.Length() - size
 }
 
 // String returns a human readable string representation of the Hand.
@@ -48,8 +47,8 @@ func (h *Hand) Add(c ...card.Card) {
 // Remove removes the Card with the given index from the Hand and
 // returns it.
 func (h *Hand) Remove(i int) (card.Card, error) {
-	if i >= len(*h) {
-		return card.Card{}, fmt.Errorf("attempted to access card %d of hand: %v", i, *h)
+	if i < 0 || i >= len(*h) {
+		return card.Card{}, fmt.Errorf("%w: %d of %d", ErrNoCard, i, len(*h))
 	}
 	c := (*h)[i]
 	*h = append((*h)[:i], (*h)[i+1:]...)
@@ -69,23 +68,23 @@ func (h *Hand) HasSuit(s card.Suit) bool {
 // NumToDiscard returns the number of cards that should be discarded
 // from this hand before re-dealing. Assumes that this hand does not
 // belong to the winner of the bid.
-func (h *Hand) NumToDiscard(trump card.Suit) int {
+func (h *Hand) NumToDiscard(trump card.Suit, size int) int {
 	numTrump := len((card.Set(*h)).TrumpCards(trump))
-	if numTrump <= h.MaxSize() {
-		return len(h.Discards(trump))
+	if numTrump <= size {
+		return len(h.Discards(trump, size))
 	}
-	return h.ExtraCards()
+	return h.ExtraCards(size)
 }
 
-// Discards takes in a trump suit and returns the indices of
-// the cards which can be discarded.
-func (h *Hand) Discards(trump card.Suit) (options []int) {
+// Discards takes in a trump suit and the hand size to discard down to and
+// returns the indices of the cards which can be discarded.
+func (h *Hand) Discards(trump card.Suit, size int) (options []int) {
 	pointCards := (card.Set(*h)).PointCards(trump)
 	trumpCards := (card.Set(*h)).TrumpCards(trump)
 	for i, c := range *h {
 		if c.Suit != trump ||
-			len(trumpCards) > h.MaxSize() && c.Points(trump) == 0 ||
-			len(pointCards) > h.MaxSize() && c.Value == card.Deuce && c.Suit == trump {
+			len(trumpCards) > size && c.Points(trump) == 0 ||
+			len(pointCards) > size && c.Value == card.Deuce && c.Suit == trump {
 			options = append(options, i)
 		}
 	}
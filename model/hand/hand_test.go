@@ -177,12 +177,12 @@ var discardTests = []struct {
 func TestDiscards(t *testing.T) {
 	for _, test := range discardTests {
 		hand := Hand(test.hand)
-		gotDiscards := hand.Discards(test.trump)
+		gotDiscards := hand.Discards(test.trump, 6)
 		if !reflect.DeepEqual(gotDiscards, test.wantDiscards) {
 			t.Errorf("%s Discards: got %v, want %v", test.name, gotDiscards, test.wantDiscards)
 		}
 
-		gotNumToDiscard := hand.NumToDiscard(test.trump)
+		gotNumToDiscard := hand.NumToDiscard(test.trump, 6)
 		if gotNumToDiscard != test.wantNumToDiscard {
 			t.Errorf("%s NumToDiscard: got %v, want %v", test.name, gotNumToDiscard, test.wantNumToDiscard)
 		}
@@ -0,0 +1,133 @@
+// Package rules describes the variants of High Five which may be played.
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"dr2w.com/hf/model/bid"
+)
+
+// deckSize is the number of cards in the deck, including the Joker.
+const deckSize = 53
+
+// Config holds every rule which differs between variants of the game.
+type Config struct {
+	// Name identifies the Config; presets are registered under their Name.
+	Name string
+	// A partnership wins once its score exceeds WinningScore, and the game
+	// also ends once any score falls below LosingScore.
+	WinningScore int
+	LosingScore  int
+	// HandSize is the number of cards each Seat plays with after the
+	// discards.
+	HandSize int
+	// Each Seat is dealt CardsPerDeal cards at a time, DealsPerHand times.
+	CardsPerDeal int
+	DealsPerHand int
+	// MinimumBid is the lowest bid other than Pass which may be made.
+	MinimumBid bid.Bid
+	// ShootTheMoon allows the doubled 14/28 and 15/30 bids.
+	ShootTheMoon bool
+	// StickTheDealer makes the dealer bid at least MinimumBid when every
+	// other Seat has passed, rather than the round being thrown in.
+	StickTheDealer bool
+}
+
+// Standard is the game as it has always been played here.
+var Standard = Config{
+	Name:           "standard",
+	WinningScore:   52,
+	LosingScore:    -104,
+	HandSize:       6,
+	CardsPerDeal:   3,
+	DealsPerHand:   3,
+	MinimumBid:     bid.B6,
+	ShootTheMoon:   true,
+	StickTheDealer: false,
+}
+
+// House is a common house variant: played to 62, opening at 7, without the
+// doubled bids, and sticking the dealer.
+var House = Config{
+	Name:           "house",
+	WinningScore:   62,
+	LosingScore:    -104,
+	HandSize:       6,
+	CardsPerDeal:   3,
+	DealsPerHand:   3,
+	MinimumBid:     bid.B7,
+	ShootTheMoon:   false,
+	StickTheDealer: true,
+}
+
+// presets maps the Names of the preset Configs to the Configs.
+var presets = map[string]Config{
+	Standard.Name: Standard,
+	House.Name:    House,
+}
+
+// Preset returns the preset Config with the given Name.
+func Preset(name string) (Config, error) {
+	c, ok := presets[name]
+	if !ok {
+		return Config{}, fmt.Errorf("unknown rules %q (known rules: %v)", name, Presets())
+	}
+	return c, nil
+}
+
+// Presets returns the Names of all preset Configs in sorted order.
+func Presets() []string {
+	var names []string
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error describing the first rule which makes the
+// Config unplayable, or nil if there is none.
+func (c Config) Validate() error {
+	dealt := c.CardsPerDeal * c.DealsPerHand
+	switch {
+	case c.WinningScore <= 0:
+		return fmt.Errorf("%s: winning score must be positive, got %d", c.Name, c.WinningScore)
+	case c.LosingScore >= 0:
+		return fmt.Errorf("%s: losing score must be negative, got %d", c.Name, c.LosingScore)
+	case c.HandSize <= 0:
+		return fmt.Errorf("%s: hand size must be positive, got %d", c.Name, c.HandSize)
+	case c.CardsPerDeal <= 0 || c.DealsPerHand <= 0:
+		return fmt.Errorf("%s: invalid deal pattern of %d cards %d times", c.Name, c.CardsPerDeal, c.DealsPerHand)
+	case dealt < c.HandSize:
+		return fmt.Errorf("%s: deals only %d cards to fill a hand of %d", c.Name, dealt, c.HandSize)
+	case 4*dealt > deckSize:
+		return fmt.Errorf("%s: deals %d cards from a deck of %d", c.Name, 4*dealt, deckSize)
+	case c.MinimumBid <= bid.Pass || c.MinimumBid > bid.B15:
+		return fmt.Errorf("%s: invalid minimum bid %s", c.Name, c.MinimumBid)
+	}
+	return nil
+}
+
+// Allows returns true iff the given bid may be made under the Config.
+func (c Config) Allows(b bid.Bid) bool {
+	if b == bid.Pass {
+		return true
+	}
+	if b < c.MinimumBid {
+		return false
+	}
+	return c.ShootTheMoon || (b != bid.B1428 && b != bid.B1530)
+}
+
+// Ladder returns the bids which may be made under the Config, Pass first and
+// then in increasing rank.
+func (c Config) Ladder() []bid.Bid {
+	var ladder []bid.Bid
+	for _, b := range bid.Values {
+		if c.Allows(b) {
+			ladder = append(ladder, b)
+		}
+	}
+	return ladder
+}
@@ -0,0 +1,58 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/model/bid"
+)
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, name := range Presets() {
+		c, err := Preset(name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if c.Name != name {
+			t.Errorf("%s: preset is named %q", name, c.Name)
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+	}
+	if _, err := Preset("bogus"); err == nil {
+		t.Errorf("Preset accepted an unknown name")
+	}
+}
+
+var validateTests = []struct {
+	name   string
+	change func(c *Config)
+}{
+	{"No Winning Score", func(c *Config) { c.WinningScore = 0 }},
+	{"Positive Losing Score", func(c *Config) { c.LosingScore = 10 }},
+	{"Empty Hand", func(c *Config) { c.HandSize = 0 }},
+	{"Short Deal", func(c *Config) { c.DealsPerHand = 1 }},
+	{"Deck Too Small", func(c *Config) { c.CardsPerDeal = 5 }},
+	{"Minimum Bid Pass", func(c *Config) { c.MinimumBid = bid.Pass }},
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range validateTests {
+		c := Standard
+		test.change(&c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected an error, but got none", test.name)
+		}
+	}
+}
+
+func TestLadder(t *testing.T) {
+	if got := Standard.Ladder(); !reflect.DeepEqual(got, bid.Values) {
+		t.Errorf("Standard: want %v, got %v", bid.Values, got)
+	}
+	want := []bid.Bid{bid.Pass, bid.B7, bid.B8, bid.B9, bid.B10, bid.B11, bid.B12, bid.B13, bid.B14, bid.B15}
+	if got := House.Ladder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("House: want %v, got %v", want, got)
+	}
+}
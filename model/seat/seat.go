@@ -2,6 +2,8 @@
 // Hands.
 package seat
 
+import "fmt"
+
 // Seat represents a side of the table where a player would sit.
 type Seat int
 
@@ -35,6 +37,25 @@ func (s Seat) String() string {
 	return Names[s]
 }
 
+// MarshalText implements encoding.TextMarshaler using the Seat's name.
+func (s Seat) MarshalText() ([]byte, error) {
+	if _, ok := Names[s]; !ok {
+		return nil, fmt.Errorf("unable to marshal unknown seat %d", int(s))
+	}
+	return []byte(Names[s]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Seat names.
+func (s *Seat) UnmarshalText(text []byte) error {
+	for st, name := range Names {
+		if name == string(text) {
+			*s = st
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown seat %q", text)
+}
+
 const (
 	None Seat = iota
 	North
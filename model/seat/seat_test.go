@@ -33,3 +33,50 @@ func TestSeatNext(t *testing.T) {
 		}
 	}
 }
+
+func TestSeatText(t *testing.T) {
+	for _, s := range append([]Seat{None}, Order...) {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", s, err)
+			continue
+		}
+		var got Seat
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("%s: round trip through %q gave %s (%v)", s, text, got, err)
+		}
+	}
+	var s Seat
+	if err := s.UnmarshalText([]byte("Northeast")); err == nil {
+		t.Errorf("expected an error unmarshalling an unknown seat")
+	}
+}
+
+func TestTeam(t *testing.T) {
+	for _, s := range Order {
+		tm := s.Team()
+		if s.Partner().Team() != tm {
+			t.Errorf("%s and its partner %s play for different teams", s, s.Partner())
+		}
+		if s.Next().Team() != tm.Opponents() {
+			t.Errorf("%s plays for %s, but %s is not its opponent", s.Next(), s.Next().Team(), tm)
+		}
+		if seats := tm.Seats(); seats[0] != s && seats[1] != s {
+			t.Errorf("%s is not one of the seats %v of %s", s, seats, tm)
+		}
+	}
+	if None.Team() != NoTeam {
+		t.Errorf("None plays for %s", None.Team())
+	}
+	for _, tm := range append([]Team{NoTeam}, Teams...) {
+		text, err := tm.MarshalText()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tm, err)
+			continue
+		}
+		var got Team
+		if err := got.UnmarshalText(text); err != nil || got != tm {
+			t.Errorf("%s: round trip through %q gave %s (%v)", tm, text, got, err)
+		}
+	}
+}
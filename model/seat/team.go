@@ -0,0 +1,80 @@
+package seat
+
+import "fmt"
+
+// Team represents a partnership of the two Seats across the table from each
+// other, which score together.
+type Team int
+
+const (
+	NoTeam Team = iota
+	NorthSouth
+	EastWest
+)
+
+var TeamNames = map[Team]string{
+	NoTeam:     "X",
+	NorthSouth: "North-South",
+	EastWest:   "East-West",
+}
+
+// Teams defines the set of valid Teams, in the order of their first Seats
+// in Order.
+var Teams = []Team{NorthSouth, EastWest}
+
+// Team returns the Team the Seat plays for.
+func (s Seat) Team() Team {
+	switch s {
+	case North, South:
+		return NorthSouth
+	case East, West:
+		return EastWest
+	}
+	return NoTeam
+}
+
+// Seats returns the two Seats which make up the Team, in play order.
+func (t Team) Seats() [2]Seat {
+	switch t {
+	case NorthSouth:
+		return [2]Seat{North, South}
+	case EastWest:
+		return [2]Seat{East, West}
+	}
+	return [2]Seat{None, None}
+}
+
+// Opponents returns the Team playing against this one.
+func (t Team) Opponents() Team {
+	switch t {
+	case NorthSouth:
+		return EastWest
+	case EastWest:
+		return NorthSouth
+	}
+	return NoTeam
+}
+
+// String returns a human readable representation of the Team.
+func (t Team) String() string {
+	return TeamNames[t]
+}
+
+// MarshalText implements encoding.TextMarshaler using the Team's name.
+func (t Team) MarshalText() ([]byte, error) {
+	if _, ok := TeamNames[t]; !ok {
+		return nil, fmt.Errorf("unable to marshal unknown team %d", int(t))
+	}
+	return []byte(TeamNames[t]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Team names.
+func (t *Team) UnmarshalText(text []byte) error {
+	for tm, name := range TeamNames {
+		if name == string(text) {
+			*t = tm
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown team %q", text)
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/seat"
+)
+
+// Capture records the cards taken by the winner of a single Trick.
+type Capture struct {
+	Winner seat.Seat
+	Cards  card.Set
+	Points int
+}
+
+// RoundResult records how a single round was scored.
+type RoundResult struct {
+	Round  int
+	Dealer seat.Seat
+	// Bidder won the bidding with Bid in the Trump suit. Bidder is seat.None
+	// and Bid is bid.Pass if every Seat passed and the round was not played.
+	Bidder seat.Seat
+	Bid    bid.Bid
+	Trump  card.Suit
+	// Points holds the points each Team took on the round's Tricks.
+	Points map[seat.Team]int
+	// Made is true iff the bidding Team took at least the points it bid.
+	// Margin is the number of points it took beyond those it bid, and is
+	// negative if it was set.
+	Made   bool
+	Margin int
+	// Delta holds the change the round made to each Team's score.
+	Delta map[seat.Team]int
+	// Tricks holds what was captured on each Trick in the order played.
+	Tricks []Capture
+}
+
+// PassedOut returns true iff every Seat passed and the round was not played.
+func (r RoundResult) PassedOut() bool {
+	return r.Bid == bid.Pass
+}
+
+// Scoresheet lists the RoundResults of a game in the order played.
+type Scoresheet []RoundResult
+
+// Totals returns each Team's running score after every round of the
+// Scoresheet.
+func (sh Scoresheet) Totals() []map[seat.Team]int {
+	totals := make([]map[seat.Team]int, len(sh))
+	running := make(map[seat.Team]int)
+	for i, r := range sh {
+		totals[i] = make(map[seat.Team]int)
+		for _, tm := range seat.Teams {
+			running[tm] += r.Delta[tm]
+			totals[i][tm] = running[tm]
+		}
+	}
+	return totals
+}
+
+// String renders the Scoresheet as a table with one line per round, showing
+// the winning bid, the points each Team took, whether the bid was made and
+// each Team's change in score and running total.
+func (sh Scoresheet) String() string {
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Round\tDealer\tBid\tTrump\tTaken\tResult\t%s\t%s\n", seat.NorthSouth, seat.EastWest)
+	totals := sh.Totals()
+	for i, r := range sh {
+		fmt.Fprintf(w, "%d\t%s\t", r.Round, r.Dealer)
+		if r.PassedOut() {
+			fmt.Fprint(w, "passed out\t\t\t\t")
+		} else {
+			result := fmt.Sprintf("made by %d", r.Margin)
+			if !r.Made {
+				result = fmt.Sprintf("set by %d", -r.Margin)
+			}
+			fmt.Fprintf(w, "%s by %s\t%s\t%d-%d\t%s\t",
+				r.Bid, r.Bidder, r.Trump,
+				r.Points[seat.NorthSouth], r.Points[seat.EastWest], result)
+		}
+		fmt.Fprintf(w, "%+d = %d\t%+d = %d\n",
+			r.Delta[seat.NorthSouth], totals[i][seat.NorthSouth],
+			r.Delta[seat.EastWest], totals[i][seat.EastWest])
+	}
+	w.Flush()
+	return buffer.String()
+}
@@ -4,28 +4,103 @@ package state
 import (
 	"bytes"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"dr2w.com/hf/model/bid"
 	"dr2w.com/hf/model/card"
 	"dr2w.com/hf/model/deck"
 	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/rules"
 	"dr2w.com/hf/model/seat"
 	"dr2w.com/hf/model/trick"
 )
 
-// HandSize determines the number of cards left in the hand after discarding.
-const HandSize = 6
-
 // State encapsulates the entire card-related state of a high five game.
 type State struct {
-	Score  map[seat.Seat]int
+	Score  map[seat.Team]int
 	Dealer seat.Seat
 	Deck   deck.Deck
 	Bids   map[seat.Seat]bid.Bid
 	Trump  card.Suit
 	Hands  map[seat.Seat]*hand.Hand
 	Played []trick.Trick
+	// Folded records the decision of each Seat which has been offered the
+	// chance to throw in; true means the Seat has thrown in its Hand.
+	Folded map[seat.Seat]bool
+	// Revealed lists, in order, the cards which have become public knowledge
+	// this round other than by being played.
+	Revealed []Reveal
+	// Discarded holds every card discarded or thrown in this round, whether
+	// or not it was revealed, and Discards the same cards by the Seat which
+	// let them go.
+	Discarded card.Set
+	Discards  map[seat.Seat]card.Set
+	// Kept records how many trump each non-winning Seat kept going into the
+	// redeal, which the table can count as the Dealer deals.
+	Kept map[seat.Seat]int
     Rounds int
+	// Scoresheet records the result of every round played so far.
+	Scoresheet Scoresheet
+	// Config holds the rules of the game this State belongs to; the zero
+	// Config stands for rules.Standard.
+	Config rules.Config
+	// Rand is the source of all randomness (shuffles, forced discards, AI
+	// tie-breaks) for the game this State belongs to. It is not serialized.
+	Rand *rand.Rand `json:"-"`
+}
+
+// Rng returns the State's source of randomness, or a freshly seeded one if
+// the State was constructed without one.
+func (s State) Rng() *rand.Rand {
+	if s.Rand == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return s.Rand
+}
+
+// Rules returns the rules of the game this State belongs to.
+func (s State) Rules() rules.Config {
+	if s.Config == (rules.Config{}) {
+		return rules.Standard
+	}
+	return s.Config
+}
+
+// Reveal records a card which has become public knowledge and the Seat it
+// belongs to.
+type Reveal struct {
+	Seat seat.Seat
+	Card card.Card
+	// Discarded is true iff the Card has left play rather than remaining
+	// in the Seat's Hand.
+	Discarded bool
+}
+
+// RevealHeld records that the given cards are publicly known to be in the
+// given Seat's Hand.
+func (s *State) RevealHeld(st seat.Seat, cards ...card.Card) {
+	for _, c := range cards {
+		s.Revealed = append(s.Revealed, Reveal{st, c, false})
+	}
+}
+
+// RevealDiscarded records that the given cards were publicly discarded from
+// the given Seat's Hand.
+func (s *State) RevealDiscarded(st seat.Seat, cards ...card.Card) {
+	for _, c := range cards {
+		s.Revealed = append(s.Revealed, Reveal{st, c, true})
+	}
+}
+
+// Discard records that the given cards left the given Seat's Hand, whether
+// discarded or thrown in.
+func (s *State) Discard(st seat.Seat, cards ...card.Card) {
+	s.Discarded = append(s.Discarded, cards...)
+	if s.Discards == nil {
+		s.Discards = make(map[seat.Seat]card.Set)
+	}
+	s.Discards[st] = append(s.Discards[st], cards...)
 }
 
 // WinningBid returns the seat with the winning Bid.
@@ -66,9 +141,20 @@ func (s State) LastPlayed() trick.Trick {
 	return s.Played[len(s.Played)-1]
 }
 
-// ToPlay returns the Seat and Hand of the next Seat to play.
+// ToPlay returns the Seat and Hand of the next Seat to play. The bid winner
+// leads the first Trick, and a Seat which has thrown in passes the lead to
+// the next Seat which has not.
 func (s State) ToPlay() (seat.Seat, *hand.Hand) {
 	st := s.LastPlayed().NextSeat(s.Trump)
+	if len(s.Played) == 0 {
+		st, _ = s.WinningBid()
+	}
+	for i := 0; s.Folded[st] && i < len(seat.Order); i++ {
+		st = st.Next()
+	}
+	if s.Folded[st] {
+		return seat.None, nil
+	}
 	h, ok := s.Hands[st]
 	if !ok {
 		return st, nil
@@ -76,6 +162,102 @@ func (s State) ToPlay() (seat.Seat, *hand.Hand) {
 	return st, h
 }
 
+// Clone returns a deep copy of the State which shares no Deck, Hands,
+// Tricks or maps with the original, so that either may be modified without
+// affecting the other. The source of randomness is shared.
+func (s State) Clone() State {
+	c := s
+	if s.Score != nil {
+		c.Score = make(map[seat.Team]int, len(s.Score))
+		for tm, sc := range s.Score {
+			c.Score[tm] = sc
+		}
+	}
+	if s.Deck != nil {
+		c.Deck = append(deck.Deck{}, s.Deck...)
+	}
+	if s.Bids != nil {
+		c.Bids = make(map[seat.Seat]bid.Bid, len(s.Bids))
+		for st, b := range s.Bids {
+			c.Bids[st] = b
+		}
+	}
+	if s.Hands != nil {
+		c.Hands = make(map[seat.Seat]*hand.Hand, len(s.Hands))
+		for st, h := range s.Hands {
+			copied := *h
+			if copied != nil {
+				copied = append(hand.Hand{}, copied...)
+			}
+			c.Hands[st] = &copied
+		}
+	}
+	if s.Played != nil {
+		c.Played = make([]trick.Trick, len(s.Played))
+		for i, t := range s.Played {
+			c.Played[i] = t.Clone()
+		}
+	}
+	if s.Folded != nil {
+		c.Folded = make(map[seat.Seat]bool, len(s.Folded))
+		for st, f := range s.Folded {
+			c.Folded[st] = f
+		}
+	}
+	if s.Revealed != nil {
+		c.Revealed = append([]Reveal{}, s.Revealed...)
+	}
+	if s.Discarded != nil {
+		c.Discarded = append(card.Set{}, s.Discarded...)
+	}
+	if s.Discards != nil {
+		c.Discards = make(map[seat.Seat]card.Set, len(s.Discards))
+		for st, d := range s.Discards {
+			c.Discards[st] = append(card.Set{}, d...)
+		}
+	}
+	if s.Kept != nil {
+		c.Kept = make(map[seat.Seat]int, len(s.Kept))
+		for st, k := range s.Kept {
+			c.Kept[st] = k
+		}
+	}
+	if s.Scoresheet != nil {
+		// RoundResults are never modified once recorded, so only the list
+		// of them is copied.
+		c.Scoresheet = append(Scoresheet{}, s.Scoresheet...)
+	}
+	return c
+}
+
+// View returns the State as seen from the given Seat: its own Hand and
+// Discards and all public information (scores, bids, trump, played tricks,
+// who has thrown in and revealed cards) are kept, while every card in the
+// other Hands and in the Deck and the discards is replaced by the zero
+// card.Card, so that only their sizes remain visible. The View shares nothing
+// with the original, not even its Rand, which would give away the Deck; it
+// has none, and whoever hands the View on may attach one of its own.
+func (s State) View(st seat.Seat) State {
+	v := s.Clone()
+	v.Rand = nil
+	v.Deck = make(deck.Deck, len(s.Deck))
+	if s.Discarded != nil {
+		v.Discarded = make(card.Set, len(s.Discarded))
+	}
+	for ds := range v.Discards {
+		if ds != st {
+			delete(v.Discards, ds)
+		}
+	}
+	for hs, h := range v.Hands {
+		if hs != st {
+			hidden := make(hand.Hand, h.Length())
+			v.Hands[hs] = &hidden
+		}
+	}
+	return v
+}
+
 // String returns a human readable representation of the State.
 func (s State) String() string {
 	var buffer bytes.Buffer
@@ -91,25 +273,54 @@ func (s State) String() string {
 		}
 	}
 	buffer.WriteString(fmt.Sprintf("Played: %v\n", s.Played))
+	buffer.WriteString(fmt.Sprintf("Folded: %v\n", s.Folded))
+	buffer.WriteString(fmt.Sprintf("Revealed: %v\n", s.Revealed))
+	buffer.WriteString(fmt.Sprintf("Discarded: %v\n", s.Discarded))
+	buffer.WriteString(fmt.Sprintf("Kept: %v\n", s.Kept))
 	buffer.WriteString(fmt.Sprintf("Rounds: %d\n", s.Rounds))
+	buffer.WriteString(fmt.Sprintf("Rules: %s\n", s.Rules().Name))
+	if len(s.Scoresheet) > 0 {
+		buffer.WriteString("Scoresheet:\n" + s.Scoresheet.String())
+	}
 	return buffer.String()
 }
 
-func (s State) NextRound(scores map[seat.Seat]int) State {
-    next := Initial(s.Dealer.Next())
-    next.Score = make(map[seat.Seat]int)
-    for st,sc := range s.Score {
-        next.Score[st] = sc + scores[st]
+// NextRound returns the starting State for the following round, with the
+// given result, numbered and attributed to the current Dealer, appended to
+// the Scoresheet, its Delta added to the running totals and a fresh Deck
+// shuffled from the same source of randomness.
+func (s State) NextRound(r RoundResult) State {
+    next := InitialWithRand(s.Dealer.Next(), s.Rng())
+    next.Config = s.Config
+    next.Score = make(map[seat.Team]int)
+    for tm,sc := range s.Score {
+        next.Score[tm] = sc + r.Delta[tm]
     }
+    r.Round = s.Rounds + 1
+    r.Dealer = s.Dealer
+    next.Scoresheet = append(append(Scoresheet(nil), s.Scoresheet...), r)
     next.Rounds = s.Rounds + 1
     return next
 }
 
 // Initial returns the starting state for a game of High Five based on the "Dealer" provided.
 func Initial(dealer seat.Seat) State {
+	return InitialWithRand(dealer, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// Seeded returns the starting state for a game of High Five whose every random
+// choice is determined by the given seed.
+func Seeded(dealer seat.Seat, seed int64) State {
+	return InitialWithRand(dealer, rand.New(rand.NewSource(seed)))
+}
+
+// InitialWithRand returns the starting state for a game of High Five which
+// draws all of its randomness from r.
+func InitialWithRand(dealer seat.Seat, r *rand.Rand) State {
 	return State{
-		Deck:   deck.Shuffled(),
+		Deck:   deck.ShuffledWith(r),
 		Dealer: dealer,
-        Score:  map[seat.Seat]int{seat.North: 0, seat.East: 0, seat.South: 0, seat.West: 0},
+        Score:  map[seat.Team]int{seat.NorthSouth: 0, seat.EastWest: 0},
+		Rand:   r,
 	}
 }
@@ -1,8 +1,12 @@
 package state
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
+	"dr2w.com/hf/model/bid"
 	"dr2w.com/hf/model/card"
 	"dr2w.com/hf/model/deck"
 	"dr2w.com/hf/model/hand"
@@ -53,3 +57,167 @@ func TestFindCard(t *testing.T) {
 		}
 	}
 }
+
+func TestSeeded(t *testing.T) {
+	a := Seeded(seat.North, 7)
+	b := Seeded(seat.North, 7)
+	if !reflect.DeepEqual(a.Deck, b.Deck) {
+		t.Errorf("same seed produced different decks:\n%v\n%v", a.Deck, b.Deck)
+	}
+	a, b = a.NextRound(RoundResult{}), b.NextRound(RoundResult{})
+	if !reflect.DeepEqual(a.Deck, b.Deck) {
+		t.Errorf("same seed produced different decks in the next round:\n%v\n%v", a.Deck, b.Deck)
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	want := Seeded(seat.East, 3)
+	want.Rand = nil
+	want.Deck, want.Hands = want.Deck[6:], map[seat.Seat]*hand.Hand{
+		seat.North: &hand.Hand{want.Deck[0], want.Deck[1], want.Deck[2]},
+		seat.South: &hand.Hand{want.Deck[3], want.Deck[4], want.Deck[5]},
+	}
+	want.Bids = map[seat.Seat]bid.Bid{seat.North: bid.Pass, seat.East: bid.B1428}
+	want.Trump = card.Hearts
+	want.Played = []trick.Trick{trick.New(card.Card{card.Joker, card.Hearts}, card.Card{})}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got State
+	if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip through %s gave %v (%v), want %v", b, got, err, want)
+	}
+}
+
+func TestView(t *testing.T) {
+	s := Seeded(seat.East, 5)
+	s.Hands = map[seat.Seat]*hand.Hand{
+		seat.North: &hand.Hand{s.Deck[0], s.Deck[1]},
+		seat.East:  &hand.Hand{s.Deck[2], s.Deck[3], s.Deck[4]},
+	}
+	s.Deck = s.Deck[5:]
+	s.Played = []trick.Trick{trick.New(card.Card{card.Ace, card.Spades})}
+	s.Score = map[seat.Team]int{seat.NorthSouth: 10}
+	s.Bids = map[seat.Seat]bid.Bid{seat.North: bid.B7}
+	s.Folded = map[seat.Seat]bool{seat.East: true}
+	s.Discard(seat.North, card.Card{card.Deuce, card.Clubs})
+	s.Discard(seat.East, card.Card{card.Three, card.Clubs})
+	v := s.View(seat.North)
+	if v.Rand != nil {
+		t.Errorf("view shares the State's Rand")
+	}
+	if !reflect.DeepEqual(*v.Hands[seat.North], *s.Hands[seat.North]) {
+		t.Errorf("own hand changed: got %v, want %v", v.Hands[seat.North], s.Hands[seat.North])
+	}
+	if got := *v.Hands[seat.East]; !reflect.DeepEqual(got, hand.Hand{{}, {}, {}}) {
+		t.Errorf("other hand not hidden: %v", &got)
+	}
+	if len(v.Deck) != len(s.Deck) || v.FindCard(s.Deck[0]) != nil {
+		t.Errorf("deck not hidden: %v", v.Deck)
+	}
+	if len(v.Discarded) != 2 || v.Discarded[0] != (card.Card{}) || !reflect.DeepEqual(v.Discards, map[seat.Seat]card.Set{seat.North: s.Discards[seat.North]}) {
+		t.Errorf("discards not hidden but for North's own: %v, %v", v.Discarded, v.Discards)
+	}
+	if !reflect.DeepEqual(v.Played, s.Played) {
+		t.Errorf("played tricks changed: got %v, want %v", v.Played, s.Played)
+	}
+	v.Hands[seat.North].Remove(0)
+	v.Played[0].Cards[seat.East] = card.Card{card.King, card.Spades}
+	v.Score[seat.NorthSouth] = 0
+	v.Bids[seat.East] = bid.B8
+	v.Folded[seat.West] = true
+	if s.Hands[seat.North].Length() != 2 || len(s.Played[0].Cards) != 1 || s.Score[seat.NorthSouth] != 10 || len(s.Bids) != 1 || len(s.Folded) != 1 {
+		t.Errorf("modifying the view modified the state: %v", s)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := Seeded(seat.North, 7)
+	h := hand.Hand{card.Card{card.Ace, card.Spades}}
+	s.Hands = map[seat.Seat]*hand.Hand{seat.North: &h}
+	s.Score = map[seat.Team]int{seat.NorthSouth: 10}
+	s.Bids = map[seat.Seat]bid.Bid{seat.North: bid.B7}
+	s.Played = []trick.Trick{trick.New(card.Card{card.Deuce, card.Clubs})}
+	s.Folded = map[seat.Seat]bool{seat.East: true}
+	s.Revealed = []Reveal{{seat.West, card.Card{card.Ten, card.Spades}, true}}
+	s.Kept = map[seat.Seat]int{seat.West: 3}
+	s.Scoresheet = Scoresheet{{Round: 1, Dealer: seat.West, Bid: bid.Pass}}
+	before := s.String()
+	c := s.Clone()
+	if c.String() != before {
+		t.Fatalf("Clone differs from original:\n%s\nvs\n%s", c, before)
+	}
+	c.Deck[0] = card.Card{}
+	c.Hands[seat.North].Add(card.Card{card.King, card.Spades})
+	(*c.Hands[seat.North])[0] = card.Card{}
+	c.Score[seat.NorthSouth] = 0
+	c.Bids[seat.North] = bid.Pass
+	c.Played[0].Cards[seat.East] = card.Card{card.Three, card.Clubs}
+	c.Folded[seat.East] = false
+	c.Revealed[0].Discarded = false
+	c.Kept[seat.West] = 0
+	c.Scoresheet[0].Round = 2
+	if after := s.String(); after != before {
+		t.Errorf("modifying a Clone changed the original:\n%s\nbecame\n%s", before, after)
+	}
+}
+
+func TestNextRoundRecordsResult(t *testing.T) {
+	s := Seeded(seat.North, 7)
+	s.Score = map[seat.Team]int{seat.NorthSouth: 10, seat.EastWest: -4}
+	s.Rounds = 2
+	r := RoundResult{
+		Bidder: seat.East,
+		Bid:    bid.B8,
+		Trump:  card.Clubs,
+		Points: map[seat.Team]int{seat.NorthSouth: 7, seat.EastWest: 8},
+		Made:   true,
+		Delta:  map[seat.Team]int{seat.NorthSouth: 7, seat.EastWest: 8},
+	}
+	next := s.NextRound(r)
+	if want := map[seat.Team]int{seat.NorthSouth: 17, seat.EastWest: 4}; !reflect.DeepEqual(next.Score, want) {
+		t.Errorf("want score %v, got %v", want, next.Score)
+	}
+	if len(next.Scoresheet) != 1 {
+		t.Fatalf("want one result on the scoresheet, got %d", len(next.Scoresheet))
+	}
+	if got := next.Scoresheet[0]; got.Round != 3 || got.Dealer != seat.North || got.Bidder != seat.East {
+		t.Errorf("recorded the wrong result: %+v", got)
+	}
+	after := next.NextRound(RoundResult{Bidder: seat.None, Bid: bid.Pass})
+	if len(after.Scoresheet) != 2 || !after.Scoresheet[1].PassedOut() || len(next.Scoresheet) != 1 {
+		t.Errorf("passed out round recorded wrongly: %v then %v", next.Scoresheet, after.Scoresheet)
+	}
+}
+
+func TestScoresheet(t *testing.T) {
+	sh := Scoresheet{
+		{
+			Round: 1, Dealer: seat.North, Bidder: seat.East, Bid: bid.B8, Trump: card.Hearts,
+			Points: map[seat.Team]int{seat.NorthSouth: 9, seat.EastWest: 6},
+			Margin: -2,
+			Delta:  map[seat.Team]int{seat.NorthSouth: 9, seat.EastWest: -8},
+		},
+		{Round: 2, Dealer: seat.East, Bid: bid.Pass},
+		{
+			Round: 3, Dealer: seat.South, Bidder: seat.North, Bid: bid.B7, Trump: card.Spades,
+			Points: map[seat.Team]int{seat.NorthSouth: 10, seat.EastWest: 5},
+			Made:   true, Margin: 3,
+			Delta: map[seat.Team]int{seat.NorthSouth: 10, seat.EastWest: 5},
+		},
+	}
+	totals := sh.Totals()
+	if want := map[seat.Team]int{seat.NorthSouth: 19, seat.EastWest: -3}; !reflect.DeepEqual(totals[2], want) {
+		t.Errorf("want totals %v, got %v", want, totals[2])
+	}
+	got := sh.String()
+	for _, want := range []string{"8 by East", "set by 2", "passed out", "made by 3", "+10 = 19", "+5 = -3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("scoresheet missing %q:\n%s", want, got)
+		}
+	}
+	if lines := strings.Count(got, "\n"); lines != 4 {
+		t.Errorf("want a header and 3 rounds, got %d lines:\n%s", lines, got)
+	}
+}
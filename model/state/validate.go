@@ -0,0 +1,157 @@
+package state
+
+import (
+	"fmt"
+
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/seat"
+)
+
+// Validate returns an error describing the first invariant of a full
+// (unredacted) State which does not hold, or nil if they all hold:
+//   - every card of the deck is in exactly one of the Deck, the Hands, the
+//     Tricks played and the discards;
+//   - the Joker and off Five are relabelled to match Trump;
+//   - every Hand holds no more cards than the current phase of the round
+//     allows;
+//   - each Trick is led by the Seat which ought to lead it and played in turn
+//     from there.
+//
+// Validate is meant for tests and debugging; a View is never valid.
+func (s State) Validate() error {
+	if err := s.validateCards(); err != nil {
+		return err
+	}
+	if err := s.validateHands(); err != nil {
+		return err
+	}
+	return s.validateTricks()
+}
+
+// cards returns every card held anywhere in the State, with the empty cards
+// played by empty Hands left out.
+func (s State) cards() card.Set {
+	cards := append(card.Set{}, s.Deck...)
+	for _, st := range seat.Order {
+		if h, ok := s.Hands[st]; ok {
+			cards = append(cards, *h...)
+		}
+	}
+	for _, t := range s.Played {
+		for _, c := range t.Cards {
+			if c != (card.Card{}) {
+				cards = append(cards, c)
+			}
+		}
+	}
+	return append(cards, s.Discarded...)
+}
+
+// natural returns the card as it is printed, undoing any relabelling to
+// match trump.
+func natural(c card.Card) card.Card {
+	switch c.Value {
+	case card.Joker:
+		return card.Card{card.Joker, card.NoSuit}
+	case card.OffFive:
+		return card.Card{card.Five, card.SameColorSuit(c.Suit)}
+	}
+	return c
+}
+
+func (s State) validateCards() error {
+	count := make(map[card.Card]int)
+	for _, c := range s.cards() {
+		switch {
+		case s.Trump == card.NoSuit && c.Value == card.Joker && c.Suit != card.NoSuit:
+			return fmt.Errorf("Joker labelled %s before trump was chosen", c.Suit)
+		case s.Trump == card.NoSuit && c.Value == card.OffFive:
+			return fmt.Errorf("off Five %s before trump was chosen", c)
+		case s.Trump != card.NoSuit && (c.Value == card.Joker || c.Value == card.OffFive) && c.Suit != s.Trump:
+			return fmt.Errorf("%s not relabelled to trump %s", c, s.Trump)
+		case s.Trump != card.NoSuit && c.Value == card.Five && c.Suit == card.SameColorSuit(s.Trump):
+			return fmt.Errorf("%s not relabelled as the off Five of trump %s", c, s.Trump)
+		}
+		count[natural(c)]++
+	}
+	full := deck.New()
+	for _, c := range full {
+		if count[c] != 1 {
+			return fmt.Errorf("found %d copies of %s, expected 1", count[c], c)
+		}
+		delete(count, c)
+	}
+	for c := range count {
+		return fmt.Errorf("found %s, which is not in the deck", c)
+	}
+	return nil
+}
+
+func (s State) validateHands() error {
+	if s.Hands == nil {
+		return nil
+	}
+	r := s.Rules()
+	dealt := r.CardsPerDeal * r.DealsPerHand
+	winner, _ := s.WinningBid()
+	played := make(map[seat.Seat]int)
+	for _, t := range s.Played {
+		for st, c := range t.Cards {
+			if c != (card.Card{}) {
+				played[st]++
+			}
+		}
+	}
+	for _, st := range seat.Order {
+		h, ok := s.Hands[st]
+		if !ok {
+			return fmt.Errorf("%s has no Hand", st)
+		}
+		switch {
+		case s.Trump == card.NoSuit && h.Length() != dealt:
+			// Bidding: every Hand is as dealt.
+			return fmt.Errorf("%s holds %d cards while bidding, expected %d", st, h.Length(), dealt)
+		case s.Trump != card.NoSuit && len(s.Played) == 0 && st != winner && h.Length() > dealt:
+			// Discarding: only the winner takes the rest of the Deck.
+			return fmt.Errorf("%s holds %d cards while discarding, at most %d expected", st, h.Length(), dealt)
+		case len(s.Played) > 0 && h.Length()+played[st] > r.HandSize:
+			return fmt.Errorf("%s holds %d cards and has played %d, more than a hand of %d", st, h.Length(), played[st], r.HandSize)
+		}
+	}
+	return nil
+}
+
+func (s State) validateTricks() error {
+	lead, _ := s.WinningBid()
+	for i, t := range s.Played {
+		first := lead
+		for j := 0; t.Out[first] && j < len(seat.Order); j++ {
+			first = first.Next()
+		}
+		if t.First != first {
+			return fmt.Errorf("trick %d led by %s, expected %s", i+1, t.First, first)
+		}
+		st, waiting := t.First, seat.None
+		for range seat.Order {
+			_, ok := t.Cards[st]
+			switch {
+			case ok && t.Out[st]:
+				return fmt.Errorf("trick %d played by %s, which has thrown in", i+1, st)
+			case ok && waiting != seat.None:
+				return fmt.Errorf("trick %d played by %s before %s", i+1, st, waiting)
+			case !ok && !t.Out[st] && waiting == seat.None:
+				waiting = st
+			}
+			st = st.Next()
+		}
+		if !t.Full() {
+			if i < len(s.Played)-1 {
+				return fmt.Errorf("trick %d incomplete, but trick %d has been played", i+1, i+2)
+			}
+			break
+		}
+		lead = t.NextSeat(s.Trump)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"testing"
+
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/hand"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/trick"
+)
+
+// dealt returns a State in which every Seat has been dealt its cards, in
+// order, from a fresh Deck.
+func dealt() State {
+	s := State{Deck: deck.New(), Dealer: seat.North, Hands: make(map[seat.Seat]*hand.Hand)}
+	for _, st := range seat.Order {
+		cards, _ := s.Deck.Deal(9)
+		h := hand.Hand(append(card.Set{}, cards...))
+		s.Hands[st] = &h
+	}
+	return s
+}
+
+// playing returns a dealt State in which East has won the bidding in Clubs,
+// every Seat has discarded down to six cards and East has led the first card
+// of the first Trick.
+func playing() State {
+	s := dealt()
+	s.Bids = map[seat.Seat]bid.Bid{seat.East: bid.B7}
+	s.Trump = card.Clubs
+	s.Deck = deck.Deck(card.Set(s.Deck).AsTrump(s.Trump))
+	for _, st := range seat.Order {
+		h := hand.Hand(card.Set(*s.Hands[st]).AsTrump(s.Trump))
+		s.Discarded = append(s.Discarded, h[6:]...)
+		h = h[:6]
+		s.Hands[st] = &h
+	}
+	lead, _ := s.Hands[seat.East].Remove(0)
+	s.Played = []trick.Trick{{Cards: map[seat.Seat]card.Card{seat.East: lead}, First: seat.East}}
+	return s
+}
+
+var validateTests = []struct {
+	name   string
+	state  func() State
+	modify func(s *State)
+	err    bool
+}{
+	{"Shuffled", Seeded(seat.North, 1).Clone, func(s *State) {}, false},
+	{"Dealt", dealt, func(s *State) {}, false},
+	{"Playing", playing, func(s *State) {}, false},
+	{"Missing Card", dealt, func(s *State) { s.Deck = s.Deck[1:] }, true},
+	{"Duplicate Card", dealt, func(s *State) { s.Deck[0] = s.Deck[1] }, true},
+	{"Extra Card", dealt, func(s *State) { s.Discarded = card.Set{{card.Ace, card.Spades}} }, true},
+	{"Joker Labelled Early", dealt, func(s *State) { (*s.Hands[seat.West])[7] = card.Card{card.Joker, card.Hearts} }, true},
+	{"Joker Not Relabelled", playing, func(s *State) { s.Trump = card.Hearts }, true},
+	{"Oversized Hand While Bidding", dealt, func(s *State) {
+		s.Hands[seat.South].Add(s.Deck[0])
+		s.Deck = s.Deck[1:]
+	}, true},
+	{"Oversized Hand While Playing", playing, func(s *State) {
+		s.Hands[seat.South].Add(s.Discarded[0])
+		s.Discarded = s.Discarded[1:]
+	}, true},
+	{"Led Out Of Turn", playing, func(s *State) { s.Played[0].First = seat.North }, true},
+	{"Played Out Of Turn", playing, func(s *State) {
+		c, _ := s.Hands[seat.West].Remove(0)
+		s.Played[0].Cards[seat.West] = c
+	}, true},
+	{"Played In Turn", playing, func(s *State) {
+		c, _ := s.Hands[seat.South].Remove(0)
+		s.Played[0].Cards[seat.South] = c
+	}, false},
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range validateTests {
+		s := test.state()
+		test.modify(&s)
+		if err := s.Validate(); err != nil && !test.err {
+			t.Errorf("%s: unexpected error: %s\n%s", test.name, err, s)
+		} else if err == nil && test.err {
+			t.Errorf("%s: expected an error, got none\n%s", test.name, s)
+		}
+	}
+}
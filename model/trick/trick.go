@@ -13,6 +13,8 @@ const Size = 4
 type Trick struct {
 	Cards map[seat.Seat]card.Card
 	First seat.Seat
+	// Out holds the Seats which have thrown in and will not play on this Trick.
+	Out map[seat.Seat]bool `json:",omitempty"`
 }
 
 // SuitLead returns the suit that was lead in this trick.
@@ -59,23 +61,33 @@ func (t Trick) WouldTakeLead(c card.Card, trump card.Suit) bool {
     return c.Beats(winning, trump, t.SuitLead())
 }
 
-// NextSeat takes the most recently played Trick and returns the Seat that should play next.
+// NextSeat takes the most recently played Trick and returns the Seat that should play next,
+// skipping any Seats which are Out.
 func (t Trick) NextSeat(trump card.Suit) seat.Seat {
-	if len(t.Cards) >= Size {
+	if t.Full() {
 		seat, _ := t.Winner(trump)
 		return seat
 	}
 	for seat, _ := range t.Cards {
-		if _, ok := t.Cards[seat.Next()]; !ok {
-			return seat.Next()
+		next := seat.Next()
+		for i := 0; t.Out[next] && i < Size; i++ {
+			next = next.Next()
+		}
+		if _, ok := t.Cards[next]; !ok {
+			return next
 		}
 	}
 	return seat.None
 }
 
+// Remaining returns the number of Seats which have yet to play on this Trick.
+func (t Trick) Remaining() int {
+	return Size - len(t.Cards) - len(t.Out)
+}
+
 // Full returns true iff all cards for this Trick have been played.
 func (t Trick) Full() bool {
-	return len(t.Cards) == Size
+	return t.Remaining() <= 0
 }
 
 // Empty returns true iff no cards have yet been played on this Trick.
@@ -83,6 +95,24 @@ func (t Trick) Empty() bool {
 	return len(t.Cards) == 0
 }
 
+// Clone returns a copy of the Trick which shares no maps with the original.
+func (t Trick) Clone() Trick {
+	c := t
+	if t.Cards != nil {
+		c.Cards = make(map[seat.Seat]card.Card, len(t.Cards))
+		for st, cd := range t.Cards {
+			c.Cards[st] = cd
+		}
+	}
+	if t.Out != nil {
+		c.Out = make(map[seat.Seat]bool, len(t.Out))
+		for st, out := range t.Out {
+			c.Out[st] = out
+		}
+	}
+	return c
+}
+
 // New is a convenience functio which constructs a trick from the first four given Cards.
 func New(cards ...card.Card) Trick {
 	t := Trick{First: seat.North, Cards: map[seat.Seat]card.Card{}}
@@ -1,6 +1,8 @@
 package trick
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"dr2w.com/hf/model/card"
@@ -109,3 +111,59 @@ func TestNextSeat(t *testing.T) {
 		}
 	}
 }
+
+func TestTrickJSON(t *testing.T) {
+	want := New(c7d, c9d, c3h)
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got Trick
+	if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip through %s gave %v (%v), want %v", b, got, err, want)
+	}
+}
+
+var outTests = []struct {
+	name      string
+	trick     Trick
+	full      bool
+	remaining int
+	next      seat.Seat
+}{
+	{
+		"Skips Out Seat",
+		Trick{Cards: map[seat.Seat]card.Card{seat.North: c7d}, First: seat.North, Out: map[seat.Seat]bool{seat.East: true}},
+		false,
+		2,
+		seat.South,
+	},
+	{
+		"Skips Several Out Seats",
+		Trick{Cards: map[seat.Seat]card.Card{seat.West: c7d}, First: seat.West, Out: map[seat.Seat]bool{seat.North: true, seat.East: true}},
+		false,
+		1,
+		seat.South,
+	},
+	{
+		"Full Without Out Seat",
+		Trick{Cards: map[seat.Seat]card.Card{seat.North: c7d, seat.South: c9d, seat.West: c3h}, First: seat.North, Out: map[seat.Seat]bool{seat.East: true}},
+		true,
+		0,
+		seat.South,
+	},
+}
+
+func TestOut(t *testing.T) {
+	for _, test := range outTests {
+		if got := test.trick.Full(); got != test.full {
+			t.Errorf("%s: Full() want %v, got %v", test.name, test.full, got)
+		}
+		if got := test.trick.Remaining(); got != test.remaining {
+			t.Errorf("%s: Remaining() want %d, got %d", test.name, test.remaining, got)
+		}
+		if got := test.trick.NextSeat(card.Diamonds); got != test.next {
+			t.Errorf("%s: NextSeat() want %v, got %v", test.name, test.next, got)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+// Package notation implements a portable, human readable text format for
+// complete games of High Five, in the spirit of PGN for chess. A game is a
+// set of headers followed by one section per round:
+//
+//	[Seed "5"]
+//	[First "East"]
+//	[Score "North-South 23 East-West 53"]
+//
+//	Round 1 Dealer South
+//	Deck 7H 2C jX AS ...
+//	Deal North:AKQ,T98,,j2 East:... South:... West:...
+//	Bid West 8
+//	Bid North Pass
+//	...
+//	Trump West H
+//	Discard North 9S 3C
+//	...
+//	ReDeal
+//	Discard West 2H 3C
+//	Trick West:AH North:9H East:2H South:jH
+//	Trick West:KH North:ThrowIn East:3H South:PlayOn South:9C
+//	...
+//	Score North-South 6 East-West -9
+//
+// A game played under rules other than rules.Standard also carries a header
+// naming its preset, such as [Rules "house"]; only preset rules may be
+// encoded. Older games which give a Score for every Seat rather than for
+// each Team are still decoded.
+//
+// Cards are written as a value followed by a suit (see card.Card.Shorthand)
+// and hands use the shorthand of card.Set.Shorthand. The Deck line is the
+// order of the deck before the round is dealt. A Seat which is out of trump
+// is shown choosing to PlayOn or ThrowIn within the Trick line where it was
+// offered the choice. The Round, Deal, ReDeal and Score lines are
+// informational and are ignored by Decode. Blank lines and lines starting
+// with ';' are comments.
+package notation
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/action"
+	"dr2w.com/hf/model/bid"
+	"dr2w.com/hf/model/card"
+	"dr2w.com/hf/model/deck"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/model/state"
+)
+
+// Encode returns the text notation for the given Record.
+func Encode(r game.Record) (string, error) {
+	var (
+		b            bytes.Buffer
+		trick        []string
+		round        int
+		dealPending  bool
+		scorePending bool
+	)
+	flushTrick := func() {
+		if len(trick) > 0 {
+			b.WriteString("Trick " + strings.Join(trick, " ") + "\n")
+			trick = nil
+		}
+	}
+	writeScore := func(s state.State) {
+		if scorePending {
+			b.WriteString("Score " + scores(s.Score) + "\n")
+			scorePending = false
+		}
+	}
+	b.WriteString(fmt.Sprintf("[Seed \"%d\"]\n", r.Seed))
+	b.WriteString(fmt.Sprintf("[First \"%s\"]\n", r.First))
+	if r.Rules != (rules.Config{}) && r.Rules != rules.Standard {
+		if preset, err := rules.Preset(r.Rules.Name); err != nil || preset != r.Rules {
+			return "", fmt.Errorf("unable to encode rules %q which are not a preset", r.Rules.Name)
+		}
+		b.WriteString(fmt.Sprintf("[Rules \"%s\"]\n", r.Rules.Name))
+	}
+	if r.Score != nil {
+		b.WriteString(fmt.Sprintf("[Score \"%s\"]\n", scores(r.Score)))
+	}
+	final, err := game.Walk(r, func(s state.State, step game.Step) error {
+		m := step.Message
+		inTrick := m.Type == action.Play || m.Type == action.ThrowIn
+		if !inTrick || s.LastPlayed().Full() || s.LastPlayed().Empty() {
+			flushTrick()
+		}
+		writeScore(s)
+		if dealPending {
+			var hands []string
+			for _, st := range seat.Order {
+				if h, ok := s.Hands[st]; ok {
+					hands = append(hands, st.String()+":"+card.Set(*h).Shorthand())
+				}
+			}
+			b.WriteString("Deal " + strings.Join(hands, " ") + "\n")
+			dealPending = false
+		}
+		switch m.Type {
+		case action.Deal:
+			round++
+			b.WriteString(fmt.Sprintf("\nRound %d Dealer %s\n", round, s.Dealer.Next()))
+			b.WriteString("Deck " + cards(card.Set(s.Deck)) + "\n")
+			dealPending = true
+		case action.Bid:
+			sel, err := single(step.Response)
+			if err != nil || sel < 0 || sel >= len(bid.Values) {
+				return fmt.Errorf("invalid bid %v for %s", step.Response, m)
+			}
+			b.WriteString(fmt.Sprintf("Bid %s %s\n", m.Seat, bid.Values[sel]))
+		case action.Trump:
+			sel, err := single(step.Response)
+			if err != nil || sel < 0 || sel >= len(card.Suits) {
+				return fmt.Errorf("invalid trump %v for %s", step.Response, m)
+			}
+			b.WriteString(fmt.Sprintf("Trump %s %s\n", m.Seat, card.Suits[sel]))
+		case action.Discard:
+			h, err := selected(s, m.Seat, step.Response)
+			if err != nil {
+				return err
+			}
+			b.WriteString(strings.TrimSpace(fmt.Sprintf("Discard %s %s", m.Seat, cards(h))) + "\n")
+		case action.ReDeal:
+			b.WriteString("ReDeal\n")
+		case action.Play:
+			h, err := selected(s, m.Seat, step.Response)
+			if err != nil || len(h) != 1 {
+				return fmt.Errorf("invalid play %v for %s", step.Response, m)
+			}
+			trick = append(trick, m.Seat.String()+":"+h[0].Shorthand())
+		case action.ThrowIn:
+			sel, err := single(step.Response)
+			if err != nil || (sel != action.PlayOn && sel != action.Fold) {
+				return fmt.Errorf("invalid throw in %v for %s", step.Response, m)
+			}
+			trick = append(trick, m.Seat.String()+":"+throwInNames[sel])
+		case action.Score:
+			scorePending = true
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	flushTrick()
+	writeScore(final)
+	return b.String(), nil
+}
+
+// Decode parses the text notation of a game and returns the corresponding
+// Record. It returns an error if the moves are not legal in sequence or if
+// the final score does not match the Score header.
+func Decode(text string) (game.Record, error) {
+	headers, moves, err := parse(text)
+	if err != nil {
+		return game.Record{}, err
+	}
+	r := game.Record{}
+	if v, ok := headers["Seed"]; ok {
+		if r.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return game.Record{}, fmt.Errorf("invalid Seed header %q: %s", v, err)
+		}
+	}
+	if r.First, err = parseSeat(headers["First"]); err != nil {
+		return game.Record{}, fmt.Errorf("invalid First header: %s", err)
+	}
+	if v, ok := headers["Rules"]; ok {
+		if r.Rules, err = rules.Preset(v); err != nil {
+			return game.Record{}, fmt.Errorf("invalid Rules header: %s", err)
+		}
+	}
+	s := state.Seeded(r.First, r.Seed)
+	s.Config = r.Rules
+	m := game.InitialMessage(r.First)
+steps:
+	for {
+		var response []int
+		switch {
+		case m.Seat == seat.None:
+		case m.Type == action.ReDeal:
+			// The ReDeal line is informational: the dealer has no choice
+			// but to take the only option.
+			response = append([]int(nil), m.Options...)
+		default:
+			if m.Type == action.Deal && len(moves) == 0 {
+				break steps
+			}
+			if len(moves) == 0 {
+				return game.Record{}, fmt.Errorf("notation ended while waiting for %s", m)
+			}
+			mv := moves[0]
+			moves = moves[1:]
+			if response, err = respond(&s, m, mv); err != nil {
+				return game.Record{}, fmt.Errorf("line %d: %s", mv.line, err)
+			}
+			if m.Type == action.Deal {
+				r.Decks = append(r.Decks, append(deck.Deck{}, s.Deck...))
+			}
+		}
+		r.Steps = append(r.Steps, game.Step{
+			Message:  copyMessage(m),
+			Response: response,
+		})
+		if m.Seat != seat.None {
+			m.Options = append([]int(nil), response...)
+		}
+		if s, m, err = action.NextState(s, m); err != nil {
+			return game.Record{}, fmt.Errorf("illegal move at step %d: %w", len(r.Steps), err)
+		}
+	}
+	r.Score = s.Score
+	if v, ok := headers["Score"]; ok {
+		want, err := parseScores(v)
+		if err != nil {
+			return game.Record{}, fmt.Errorf("invalid Score header %q: %s", v, err)
+		}
+		for _, tm := range seat.Teams {
+			if want[tm] != s.Score[tm] {
+				return game.Record{}, fmt.Errorf("moves reach score %v, but Score header is %v", s.Score, want)
+			}
+		}
+	}
+	return r, nil
+}
+
+// move is a single player input parsed from the notation.
+type move struct {
+	line   int
+	kind   action.Type
+	seat   seat.Seat
+	fields []string
+}
+
+// throwInNames name the decisions made in response to a ThrowIn Message,
+// which appear within Trick lines in place of a card.
+var throwInNames = map[int]string{
+	action.PlayOn: "PlayOn",
+	action.Fold:   "ThrowIn",
+}
+
+var throwInSelections = map[string]int{
+	"PlayOn":  action.PlayOn,
+	"ThrowIn": action.Fold,
+}
+
+var headerPattern = regexp.MustCompile(`^\[(\w+)\s+"(.*)"\]$`)
+
+// parse splits the notation into its headers and the ordered list of moves.
+func parse(text string) (map[string]string, []move, error) {
+	headers := make(map[string]string)
+	var moves []move
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if h := headerPattern.FindStringSubmatch(line); h != nil {
+			headers[h[1]] = h[2]
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "Round", "Deal", "ReDeal", "Score":
+			continue
+		case "Deck":
+			moves = append(moves, move{i + 1, action.Deal, seat.None, fields[1:]})
+		case "Bid", "Trump", "Discard":
+			if len(fields) < 2 {
+				return nil, nil, fmt.Errorf("line %d: missing seat in %q", i+1, line)
+			}
+			st, err := parseSeat(fields[1])
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %s", i+1, err)
+			}
+			kind := map[string]action.Type{"Bid": action.Bid, "Trump": action.Trump, "Discard": action.Discard}[fields[0]]
+			moves = append(moves, move{i + 1, kind, st, fields[2:]})
+		case "Trick":
+			for _, play := range fields[1:] {
+				parts := strings.Split(play, ":")
+				if len(parts) != 2 {
+					return nil, nil, fmt.Errorf("line %d: invalid play %q", i+1, play)
+				}
+				st, err := parseSeat(parts[0])
+				if err != nil {
+					return nil, nil, fmt.Errorf("line %d: %s", i+1, err)
+				}
+				kind := action.Play
+				if _, ok := throwInSelections[parts[1]]; ok {
+					kind = action.ThrowIn
+				}
+				moves = append(moves, move{i + 1, kind, st, parts[1:]})
+			}
+		default:
+			return nil, nil, fmt.Errorf("line %d: unrecognized line %q", i+1, line)
+		}
+	}
+	return headers, moves, nil
+}
+
+// respond converts a move into the response to the given Message, installing
+// the Deck into the State for Deal moves.
+func respond(s *state.State, m action.Message, mv move) ([]int, error) {
+	if mv.kind != m.Type {
+		return nil, fmt.Errorf("expected %s by %s, found %s", m.Type, m.Seat, mv.kind)
+	}
+	if m.Type == action.Deal {
+		d, err := parseCards(mv.fields)
+		if err != nil {
+			return nil, err
+		}
+		s.Deck = deck.Deck(d)
+		return []int{0}, nil
+	}
+	if mv.seat != m.Seat {
+		return nil, fmt.Errorf("expected %s by %s, found %s by %s", m.Type, m.Seat, mv.kind, mv.seat)
+	}
+	var response []int
+	switch m.Type {
+	case action.Bid:
+		for _, b := range bid.Values {
+			if len(mv.fields) == 1 && b.String() == mv.fields[0] {
+				response = []int{int(b)}
+			}
+		}
+	case action.ThrowIn:
+		if sel, ok := throwInSelections[mv.fields[0]]; ok {
+			response = []int{sel}
+		}
+	case action.Trump:
+		for i, suit := range card.Suits {
+			if len(mv.fields) == 1 && suit.String() == mv.fields[0] {
+				response = []int{i}
+			}
+		}
+	case action.Discard, action.Play:
+		cs, err := parseCards(mv.fields)
+		if err != nil {
+			return nil, err
+		}
+		response = []int{}
+		used := make(map[int]bool)
+		for _, c := range cs {
+			found := false
+			for i, hc := range *s.Hands[m.Seat] {
+				if hc == c && !used[i] {
+					used[i], found = true, true
+					response = append(response, i)
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%s does not hold %s", m.Seat, c.Shorthand())
+			}
+		}
+	}
+	if response == nil {
+		return nil, fmt.Errorf("unable to interpret %s %v", mv.kind, mv.fields)
+	}
+	for _, sel := range response {
+		if !contains(m.Options, sel) {
+			return nil, fmt.Errorf("%s %v is not a legal %s for %s", mv.kind, mv.fields, m.Type, m.Seat)
+		}
+	}
+	return response, nil
+}
+
+// selected returns the cards at the given indices of the Seat's Hand.
+func selected(s state.State, st seat.Seat, indices []int) (card.Set, error) {
+	h, ok := s.Hands[st]
+	if !ok {
+		return nil, fmt.Errorf("%s has no hand", st)
+	}
+	var cs card.Set
+	for _, i := range indices {
+		if i < 0 || i >= h.Length() {
+			return nil, fmt.Errorf("invalid selection %d from the hand of %s (%s)", i, st, h)
+		}
+		cs = append(cs, h.Get(i))
+	}
+	return cs, nil
+}
+
+func single(response []int) (int, error) {
+	if len(response) != 1 {
+		return 0, fmt.Errorf("expected a single selection, got %v", response)
+	}
+	return response[0], nil
+}
+
+func contains(options []int, sel int) bool {
+	for _, o := range options {
+		if o == sel {
+			return true
+		}
+	}
+	return false
+}
+
+func copyMessage(m action.Message) action.Message {
+	m.Options = append([]int(nil), m.Options...)
+	return m
+}
+
+// cards returns the space separated shorthand for the given cards.
+func cards(cs card.Set) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = c.Shorthand()
+	}
+	return strings.Join(s, " ")
+}
+
+func parseCards(fields []string) (card.Set, error) {
+	cs := card.Set{}
+	for _, f := range fields {
+		c, err := card.FromShorthand(f)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func parseSeat(s string) (seat.Seat, error) {
+	for _, st := range seat.Order {
+		if st.String() == s {
+			return st, nil
+		}
+	}
+	return seat.None, fmt.Errorf("unknown seat %q", s)
+}
+
+// parseTeam returns the Team with the given name or, for older games, the
+// Team of the Seat with the given name.
+func parseTeam(s string) (seat.Team, error) {
+	for _, tm := range seat.Teams {
+		if tm.String() == s {
+			return tm, nil
+		}
+	}
+	if st, err := parseSeat(s); err == nil {
+		return st.Team(), nil
+	}
+	return seat.NoTeam, fmt.Errorf("unknown team %q", s)
+}
+
+// scores returns the notation for a set of per-team scores.
+func scores(m map[seat.Team]int) string {
+	var s []string
+	for _, tm := range seat.Teams {
+		s = append(s, fmt.Sprintf("%s %d", tm, m[tm]))
+	}
+	return strings.Join(s, " ")
+}
+
+func parseScores(s string) (map[seat.Team]int, error) {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("expected team and score pairs")
+	}
+	m := make(map[seat.Team]int)
+	for i := 0; i < len(fields); i += 2 {
+		tm, err := parseTeam(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		sc, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := m[tm]; ok && prev != sc {
+			return nil, fmt.Errorf("partners of %s scored %d and %d", tm, prev, sc)
+		}
+		m[tm] = sc
+	}
+	return m, nil
+}
@@ -0,0 +1,145 @@
+package notation
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+func resolved(t *testing.T, seed int64) game.Record {
+	g, err := game.NewSeeded(seed, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewSeeded: %s", err)
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	return g.Record
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, seed := range []int64{0, 1, 2} {
+		want := resolved(t, seed)
+		text, err := Encode(want)
+		if err != nil {
+			t.Fatalf("Seed %d: Encode: %s", seed, err)
+		}
+		got, err := Decode(text)
+		if err != nil {
+			t.Fatalf("Seed %d: Decode: %s\n%s", seed, err, text)
+		}
+		if len(got.Steps) != len(want.Steps) || len(got.Decks) != len(want.Decks) {
+			t.Fatalf("Seed %d: got %d steps and %d decks, want %d and %d",
+				seed, len(got.Steps), len(got.Decks), len(want.Steps), len(want.Decks))
+		}
+		for i := range want.Steps {
+			g, w := got.Steps[i], want.Steps[i]
+			if g.Message.Type != w.Message.Type || g.Message.Seat != w.Message.Seat ||
+				!sameInts(g.Message.Options, w.Message.Options) ||
+				(w.Message.Seat != seat.None && !sameInts(g.Response, w.Response)) {
+				t.Errorf("Seed %d, step %d: got %s -> %v, want %s -> %v",
+					seed, i, g.Message, g.Response, w.Message, w.Response)
+			}
+		}
+		if _, err := game.Replay(got); err != nil {
+			t.Errorf("Seed %d: decoded record does not replay: %s", seed, err)
+		}
+	}
+}
+
+var decodeErrorTests = []struct {
+	name    string
+	replace string
+	with    string
+}{
+	{"Wrong Score", "[Score \"", "[Score \"North-South 1000 East-West 0\"]\n; "},
+	{"Unknown Team", "[Score \"", "[Score \"Northeast 0\"]\n; "},
+	{"Unknown Line", "ReDeal", "Redeal"},
+	{"Unknown Seat", "[First \"East\"]", "[First \"Nowhere\"]"},
+	{"Bad Card", "Deck ", "Deck ZZ "},
+}
+
+func TestDecodeErrors(t *testing.T) {
+	text, err := Encode(resolved(t, 5))
+	if err != nil {
+		t.Fatalf("Encode: %s", err)
+	}
+	for _, test := range decodeErrorTests {
+		if _, err := Decode(strings.Replace(text, test.replace, test.with, 1)); err == nil {
+			t.Errorf("%s: expected an error, but got none", test.name)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	g, err := game.NewWithRules(rules.House, 3, seat.East, ai.DRW, ai.DRW, ai.DRW, ai.DRW)
+	if err != nil {
+		t.Fatalf("NewWithRules: %s", err)
+	}
+	if err := g.Resolve(); err != nil {
+		t.Fatalf("Resolve: %s\n%s", err, g)
+	}
+	text, err := Encode(g.Record)
+	if err != nil {
+		t.Fatalf("Encode: %s", err)
+	}
+	if !strings.Contains(text, "[Rules \"house\"]") {
+		t.Errorf("missing Rules header:\n%s", text)
+	}
+	got, err := Decode(text)
+	if err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if got.Rules != rules.House {
+		t.Errorf("decoded rules %v, want %v", got.Rules, rules.House)
+	}
+	custom := g.Record
+	custom.Rules.WinningScore = 100
+	if _, err := Encode(custom); err == nil {
+		t.Errorf("Encode accepted rules which are not a preset")
+	}
+}
+
+func TestDecodeSeatScores(t *testing.T) {
+	r := resolved(t, 5)
+	text, err := Encode(r)
+	if err != nil {
+		t.Fatalf("Encode: %s", err)
+	}
+	ns, ew := r.Score[seat.NorthSouth], r.Score[seat.EastWest]
+	old := fmt.Sprintf("[Score \"North %d East %d South %d West %d\"]", ns, ew, ns, ew)
+	text = strings.Replace(text, fmt.Sprintf("[Score \"%s\"]", scores(r.Score)), old, 1)
+	if !strings.Contains(text, old) {
+		t.Fatalf("unable to find the Score header in:\n%s", text)
+	}
+	if _, err := Decode(text); err != nil {
+		t.Errorf("Decode of per-seat scores: %s", err)
+	}
+	mismatched := fmt.Sprintf("[Score \"North %d East %d South %d West %d\"]", ns, ew, ns+1, ew)
+	if _, err := Decode(strings.Replace(text, old, mismatched, 1)); err == nil {
+		t.Errorf("Decode accepted partners with different scores")
+	}
+}
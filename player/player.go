@@ -4,6 +4,19 @@ package player
 import (
     "dr2w.com/hf/model/state"
     "dr2w.com/hf/model/action"
+    "dr2w.com/hf/model/seat"
+)
+
+// The responses Play may return alone instead of answering the Message.
+const (
+    // Takeback asks to take back the Player's last decision.
+    Takeback = -1
+    // Forfeit gives up the Player's choice, which the Game makes for it if
+    // it allows forfeits.
+    Forfeit = -2
+    // Abandon stops the Game where it is, as when whoever hosts the Player
+    // shuts down, so that it may be resumed later.
+    Abandon = -3
 )
 
 // Player defines the basic interface needed for an entity (human or AI) to play the game.
@@ -11,3 +24,16 @@ type Player interface {
     Play(state state.State, message action.Message) []int
     Update(state state.State, t action.Type) 
 }
+
+// TakebackResponder is implemented by Players which decide whether to allow
+// another Player to take back its last decision. Players which do not
+// implement it always allow takebacks.
+type TakebackResponder interface {
+    AcceptTakeback(state state.State, requester seat.Seat) bool
+}
+
+// RejectionReceiver is implemented by Players which are told why their
+// response was rejected before they are asked again.
+type RejectionReceiver interface {
+    Rejected(err error)
+}
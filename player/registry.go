@@ -0,0 +1,42 @@
+package player
+
+import (
+    "fmt"
+    "sort"
+
+    "dr2w.com/hf/model/seat"
+)
+
+// Factory creates a Player to sit in the given Seat.
+type Factory func(st seat.Seat) Player
+
+// registry maps the names of all known kinds of Player to their Factories.
+var registry = make(map[string]Factory)
+
+// Register makes a kind of Player available by name. It is intended to be
+// called from init functions and panics if the name is already taken.
+func Register(name string, f Factory) {
+    if _, ok := registry[name]; ok {
+        panic(fmt.Sprintf("player %q registered twice", name))
+    }
+    registry[name] = f
+}
+
+// New returns a new Player of the named kind sitting in the given Seat.
+func New(name string, st seat.Seat) (Player, error) {
+    f, ok := registry[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown player %q (known players: %v)", name, Names())
+    }
+    return f(st), nil
+}
+
+// Names returns the names of all registered kinds of Player in sorted order.
+func Names() []string {
+    var names []string
+    for name := range registry {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
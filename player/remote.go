@@ -0,0 +1,222 @@
+package player
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "time"
+
+    "dr2w.com/hf/model/action"
+    "dr2w.com/hf/model/seat"
+    "dr2w.com/hf/model/state"
+)
+
+// The kinds of Envelope a Remote sends to its client.
+const (
+    // KindSeat tells the client which Seat it has been given. It is sent
+    // once, before any other Envelope.
+    KindSeat = "seat"
+    // KindPlay asks the client to answer the Message.
+    KindPlay = "play"
+    // KindUpdate tells the client the game has moved on with a Message of
+    // the given Type.
+    KindUpdate = "update"
+    // KindTakeback asks the client whether Requester may take back its last
+    // decision.
+    KindTakeback = "takeback"
+    // KindRejected tells the client why its last answer was not allowed.
+    KindRejected = "rejected"
+)
+
+// Envelope is sent by a Remote to its client, encoded as a single line of
+// JSON. The State is always the client's own view of the game.
+type Envelope struct {
+    Kind      string
+    Seat      seat.Seat
+    State     *state.State    `json:",omitempty"`
+    Message   *action.Message `json:",omitempty"`
+    Type      action.Type     `json:",omitempty"`
+    Requester seat.Seat       `json:",omitempty"`
+    Error     string          `json:",omitempty"`
+}
+
+// Reply is sent by a client in answer to a KindPlay or KindTakeback
+// Envelope, encoded as a single line of JSON.
+type Reply struct {
+    Selection []int `json:",omitempty"`
+    Accept    bool  `json:",omitempty"`
+}
+
+// deadliner is implemented by connections, such as a net.Conn, which can
+// give up waiting for a read.
+type deadliner interface {
+    SetReadDeadline(t time.Time) error
+}
+
+// Remote is a Player whose decisions are made by a client at the other end
+// of a connection, which may be any stream such as a TCP connection. It
+// relays every request and update to the client and waits for its replies;
+// the client is typically running Relay.
+//
+// Once the connection fails the Remote stops talking to the client: Play
+// returns no selection, which the Game rejects, and takebacks are refused.
+type Remote struct {
+    Seat seat.Seat
+    // Timeout bounds how long the client is given to answer, if the
+    // connection supports deadlines; zero waits forever.
+    Timeout time.Duration
+    conn    io.ReadWriteCloser
+    enc     *json.Encoder
+    dec     *json.Decoder
+    err     error
+}
+
+// NewRemote returns a Remote sitting in the given Seat whose client is at the
+// other end of conn, and tells the client its Seat.
+func NewRemote(st seat.Seat, conn io.ReadWriteCloser) (*Remote, error) {
+    p := &Remote{
+        Seat: st,
+        conn: conn,
+        enc:  json.NewEncoder(conn),
+        dec:  json.NewDecoder(conn),
+    }
+    if err := p.send(Envelope{Kind: KindSeat, Seat: st}); err != nil {
+        return nil, err
+    }
+    return p, nil
+}
+
+func (p *Remote) String() string {
+    return fmt.Sprintf("Remote(%s)", p.Seat)
+}
+
+// Err returns the error which broke the connection to the client, if any.
+func (p *Remote) Err() error {
+    return p.err
+}
+
+// Close closes the connection to the client.
+func (p *Remote) Close() error {
+    if p.err == nil {
+        p.err = errors.New("remote player closed")
+    }
+    return p.conn.Close()
+}
+
+// send writes the Envelope to the client unless the connection has failed.
+func (p *Remote) send(e Envelope) error {
+    if p.err != nil {
+        return p.err
+    }
+    if err := p.enc.Encode(e); err != nil {
+        p.err = fmt.Errorf("sending %s to %s: %w", e.Kind, p.Seat, err)
+    }
+    return p.err
+}
+
+// ask sends the Envelope and waits for the client's Reply.
+func (p *Remote) ask(e Envelope) (Reply, error) {
+    var r Reply
+    if err := p.send(e); err != nil {
+        return r, err
+    }
+    if d, ok := p.conn.(deadliner); ok && p.Timeout > 0 {
+        d.SetReadDeadline(time.Now().Add(p.Timeout))
+        defer d.SetReadDeadline(time.Time{})
+    }
+    if err := p.dec.Decode(&r); err != nil {
+        p.err = fmt.Errorf("awaiting %s from %s: %w", e.Kind, p.Seat, err)
+        return r, p.err
+    }
+    return r, nil
+}
+
+// Play implements Player by asking the client to answer the Message.
+func (p *Remote) Play(s state.State, m action.Message) []int {
+    r, err := p.ask(Envelope{Kind: KindPlay, Seat: p.Seat, State: &s, Message: &m})
+    if err != nil {
+        return nil
+    }
+    return r.Selection
+}
+
+// Update implements Player by passing the new State on to the client.
+func (p *Remote) Update(s state.State, t action.Type) {
+    p.send(Envelope{Kind: KindUpdate, Seat: p.Seat, State: &s, Type: t})
+}
+
+// AcceptTakeback implements TakebackResponder by asking the client.
+func (p *Remote) AcceptTakeback(s state.State, requester seat.Seat) bool {
+    r, err := p.ask(Envelope{Kind: KindTakeback, Seat: p.Seat, State: &s, Requester: requester})
+    return err == nil && r.Accept
+}
+
+// Rejected implements RejectionReceiver by passing the reason on to the
+// client.
+func (p *Remote) Rejected(err error) {
+    p.send(Envelope{Kind: KindRejected, Seat: p.Seat, Error: err.Error()})
+}
+
+// Relay plays a game hosted at the other end of conn on behalf of a Remote.
+// It waits to be told its Seat, seats a Player made by f there and then
+// answers every request with that Player's decisions until the host closes
+// the connection.
+func Relay(conn io.ReadWriter, f Factory) error {
+    var (
+        enc = json.NewEncoder(conn)
+        dec = json.NewDecoder(conn)
+        p   Player
+    )
+    for {
+        var e Envelope
+        if err := dec.Decode(&e); err == io.EOF {
+            return nil
+        } else if err != nil {
+            return fmt.Errorf("reading from host: %w", err)
+        }
+        if p == nil && e.Kind != KindSeat {
+            return fmt.Errorf("host sent %s before assigning a seat", e.Kind)
+        }
+        var (
+            reply Reply
+            answer = true
+        )
+        switch e.Kind {
+        case KindSeat:
+            p, answer = f(e.Seat), false
+        case KindPlay:
+            if e.State == nil || e.Message == nil {
+                return fmt.Errorf("host sent %s without a state and message", e.Kind)
+            }
+            reply.Selection = p.Play(*e.State, *e.Message)
+        case KindUpdate:
+            if e.State == nil {
+                return fmt.Errorf("host sent %s without a state", e.Kind)
+            }
+            p.Update(*e.State, e.Type)
+            answer = false
+        case KindTakeback:
+            reply.Accept = true
+            if r, ok := p.(TakebackResponder); ok {
+                if e.State == nil {
+                    return fmt.Errorf("host sent %s without a state", e.Kind)
+                }
+                reply.Accept = r.AcceptTakeback(*e.State, e.Requester)
+            }
+        case KindRejected:
+            if r, ok := p.(RejectionReceiver); ok {
+                r.Rejected(errors.New(e.Error))
+            }
+            answer = false
+        default:
+            return fmt.Errorf("host sent unknown envelope kind %q", e.Kind)
+        }
+        if !answer {
+            continue
+        }
+        if err := enc.Encode(reply); err != nil {
+            return fmt.Errorf("replying to host: %w", err)
+        }
+    }
+}
@@ -0,0 +1,128 @@
+package player
+
+import (
+    "errors"
+    "net"
+    "reflect"
+    "testing"
+
+    "dr2w.com/hf/model/action"
+    "dr2w.com/hf/model/card"
+    "dr2w.com/hf/model/hand"
+    "dr2w.com/hf/model/seat"
+    "dr2w.com/hf/model/state"
+)
+
+// scripted is a Player which picks the first options offered, accepts
+// takebacks only from North and records everything it is told.
+type scripted struct {
+    seat     seat.Seat
+    states   []state.State
+    updates  []action.Type
+    rejected []string
+}
+
+func (p *scripted) Play(s state.State, m action.Message) []int {
+    p.states = append(p.states, s)
+    return m.Options[:m.Expect]
+}
+
+func (p *scripted) Update(s state.State, t action.Type) {
+    p.states = append(p.states, s)
+    p.updates = append(p.updates, t)
+}
+
+func (p *scripted) AcceptTakeback(s state.State, requester seat.Seat) bool {
+    return requester == seat.North
+}
+
+func (p *scripted) Rejected(err error) {
+    p.rejected = append(p.rejected, err.Error())
+}
+
+// loopback connects a Remote in the given Seat to a client relaying for a
+// scripted Player, returning both along with the channel the client's
+// result is sent on once it stops.
+func loopback(t *testing.T, st seat.Seat) (*Remote, *scripted, <-chan error) {
+    host, client := net.Pipe()
+    p := &scripted{}
+    done := make(chan error, 1)
+    go func() {
+        done <- Relay(client, func(st seat.Seat) Player {
+            p.seat = st
+            return p
+        })
+        client.Close()
+    }()
+    r, err := NewRemote(st, host)
+    if err != nil {
+        t.Fatalf("NewRemote: %s", err)
+    }
+    return r, p, done
+}
+
+func TestRemote(t *testing.T) {
+    r, p, done := loopback(t, seat.East)
+    s := state.Initial(seat.North)
+    s.Hands = map[seat.Seat]*hand.Hand{
+        seat.East:  &hand.Hand{{card.Ace, card.Hearts}, {card.Joker, card.NoSuit}},
+        seat.South: &hand.Hand{{}, {}},
+    }
+    s = s.View(seat.East)
+    s.Rand = nil
+
+    r.Update(s, action.Bid)
+    if got := r.Play(s, action.Message{Type: action.Play, Seat: seat.East, Options: []int{1, 0}, Expect: 1}); !reflect.DeepEqual(got, []int{1}) {
+        t.Errorf("Play = %v, want [1]", got)
+    }
+    r.Rejected(errors.New("not allowed"))
+    if !r.AcceptTakeback(s, seat.North) {
+        t.Errorf("takeback by North refused")
+    }
+    if r.AcceptTakeback(s, seat.South) {
+        t.Errorf("takeback by South accepted")
+    }
+    if err := r.Close(); err != nil {
+        t.Fatalf("Close: %s", err)
+    }
+    if err := <-done; err != nil {
+        t.Fatalf("Relay: %s", err)
+    }
+
+    if p.seat != seat.East {
+        t.Errorf("client seated in %s, want %s", p.seat, seat.East)
+    }
+    if !reflect.DeepEqual(p.updates, []action.Type{action.Bid}) {
+        t.Errorf("client updated with %v, want [%s]", p.updates, action.Bid)
+    }
+    if len(p.states) != 2 || !reflect.DeepEqual(p.states[1].Hands, s.Hands) {
+        t.Errorf("client saw states %v, want the hands %v", p.states, s.Hands)
+    }
+    if !reflect.DeepEqual(p.rejected, []string{"not allowed"}) {
+        t.Errorf("client told of rejections %q", p.rejected)
+    }
+}
+
+func TestRemoteDisconnected(t *testing.T) {
+    host, client := net.Pipe()
+    go func() {
+        // Take the Seat, then hang up.
+        buf := make([]byte, 512)
+        client.Read(buf)
+        client.Close()
+    }()
+    r, err := NewRemote(seat.West, host)
+    if err != nil {
+        t.Fatalf("NewRemote: %s", err)
+    }
+    m := action.Message{Type: action.Bid, Seat: seat.West, Options: []int{0}, Expect: 1}
+    if got := r.Play(state.State{}, m); got != nil {
+        t.Errorf("Play = %v after the client hung up, want nil", got)
+    }
+    if r.Err() == nil {
+        t.Errorf("Err = nil after the client hung up")
+    }
+    if r.AcceptTakeback(state.State{}, seat.North) {
+        t.Errorf("takeback accepted after the client hung up")
+    }
+}
@@ -1,7 +1,10 @@
 package player
 
 import (
+    "errors"
     "fmt"
+    "io"
+    "os"
     "strings"
     "strconv"
     "time"
@@ -15,10 +18,21 @@ import (
 
 var clearLines = 40
 
+// stdin is where the user's answers to takeback requests are read from.
+var stdin io.Reader = os.Stdin
+
+// takebackPrompts is the number of times the user is asked about a takeback
+// before it is refused.
+const takebackPrompts = 3
+
 type Stdio struct {
     Seat seat.Seat
 }
 
+func init() {
+    Register("Stdio", func(st seat.Seat) Player { return Stdio{st} })
+}
+
 // Play prints the relevant State and Message Options to stdout and pulls the selection
 // from stdin.
 func (p Stdio) Play(s state.State, m action.Message) []int {
@@ -44,7 +58,10 @@ func clearScreen() {
 
 // displayState prints the current game state to stdout in an informative format. 
 func displayState(s state.State, t action.Type, st seat.Seat) {
-    if t == action.Bid || t == action.Trump {
+    if t == action.Deal && len(s.Scoresheet) > 0 {
+        // Between hands, before the next deal.
+        displayScoresheet(s)
+    } else if t == action.Bid || t == action.Trump {
         displayBidding(s, st)
     } else {
         displayPlays(s, st)
@@ -60,7 +77,13 @@ func displayBidding(s state.State, st seat.Seat) {
 
 // displayScores prints out a single line with the current scores.
 func displayScores(s state.State) {
-    fmt.Printf("Score:\nEast/West: %d\tNorth/South: %d\n", s.Score[seat.East], s.Score[seat.North])
+    fmt.Printf("Score:\n%s: %d\t%s: %d\n",
+               seat.EastWest, s.Score[seat.EastWest], seat.NorthSouth, s.Score[seat.NorthSouth])
+}
+
+// displayScoresheet shows the result of every round played so far.
+func displayScoresheet(s state.State) {
+    fmt.Printf("Scoresheet:\n%s", s.Scoresheet)
 }
 
 func displayBids(s state.State) {
@@ -75,9 +98,22 @@ func displayBids(s state.State) {
 // displayPlays shows the trump and bid as well as the current hand and current play.
 func displayPlays(s state.State, st seat.Seat) {
     displayWinningBid(s)
+    displayRevealed(s)
     displayPlay(s)
 }
 
+// displayRevealed lists the cards which have been exposed this round other
+// than by being played.
+func displayRevealed(s state.State) {
+    for _, r := range s.Revealed {
+        if r.Discarded {
+            fmt.Printf("%s discarded %s\n", r.Seat, r.Card)
+        } else {
+            fmt.Printf("%s holds %s\n", r.Seat, r.Card)
+        }
+    }
+}
+
 // displayWinningBid shows the suit and value of the winning bid.
 func displayWinningBid(s state.State) {
     st, bid := s.WinningBid()
@@ -142,9 +178,9 @@ func displayHand(s state.State, st seat.Seat, options []int) {
 func displayBid(m action.Message) {
 	names := make([]string, len(m.Options))
 	values := make([]string, len(m.Options))
-	for i := range m.Options {
-	    names[i] = bid.Bid(i).String()
-	    values[i] = "[" + strconv.Itoa(i) + "]"
+	for i, o := range m.Options {
+	    names[i] = bid.Bid(o).String()
+	    values[i] = "[" + strconv.Itoa(o) + "]"
         }
 	fmt.Printf("\n\n%s\n%s\n", strings.Join(names, "\t"), strings.Join(values, "\t"))
 	fmt.Printf("Please select a bid: ")
@@ -176,13 +212,40 @@ func displayChoice(m action.Message, s state.State) {
 		fmt.Printf("\nPlease select %d cards to discard (use commas):", m.Expect)
 	case action.Trump:
 		displayTrumpOptions(m, s)
+	case action.ThrowIn:
+		displayHand(s, m.Seat, []int{})
+		fmt.Printf("\nYou are out of trump. Play on [%d] or throw in your hand [%d]: ", action.PlayOn, action.Fold)
 	default:
     		fmt.Printf("\n\n%s: ", m)
     }
+    fmt.Printf(" (or \"undo\") ")
+}
+
+// AcceptTakeback asks the user whether to let the requesting Seat take back
+// its last decision, refusing it if the user does not answer.
+func (p Stdio) AcceptTakeback(s state.State, requester seat.Seat) bool {
+    for i := 0; i < takebackPrompts; i++ {
+        fmt.Printf("\n%s asks to take back their last choice. Allow it? [y/n]: ", requester)
+        text := ""
+        _, err := fmt.Fscanln(stdin, &text)
+        if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+            return false
+        }
+        if err == nil {
+            return strings.HasPrefix(strings.ToLower(text), "y")
+        }
+    }
+    return false
+}
+
+// Rejected tells the user why their last choice was not allowed.
+func (p Stdio) Rejected(err error) {
+    fmt.Printf("\n%s, please try again.\n", err)
+    time.Sleep(2*time.Second)
 }
 
 // solicitChoice prompts the user to select one or more of a set of options and returns
-// the selections.
+// the selections, or Takeback if the user enters "undo".
 func solicitChoice(m action.Message, s state.State) []int {
     displayChoice(m, s)
     text := ""
@@ -191,6 +254,9 @@ func solicitChoice(m action.Message, s state.State) []int {
             fmt.Println("error when reading from stdin, please try again.")
             return solicitChoice(m, s)
     }
+    if text == "undo" {
+        return []int{Takeback}
+    }
     selections := strings.Split(text, ",")
     result := make([]int, len(selections))
     for i, sel := range selections {
@@ -199,19 +265,9 @@ func solicitChoice(m action.Message, s state.State) []int {
             fmt.Printf("\ncan't interpret %q as a number:\n%s\n", result[i], err)
             return solicitChoice(m, s)
         }
-	var valid bool
-	for _, option := range m.Options {
-	    if result[i] == option {
-		valid = true
-	    }
-	}
-	if !valid {
-	    fmt.Printf("\ninvalid selection: %d, please try again.", result[i])
-	    return solicitChoice(m, s)
-	}
     }
-    if len(result) != m.Expect {
-	fmt.Printf("\ninvalid selection: must choose %d, you chose %d.", m.Expect, len(result))
+    if err := m.Validate(result); err != nil {
+	fmt.Printf("\n%s, please try again.", err)
 	return solicitChoice(m, s)
     }
     fmt.Printf("Selected: %v", result)
@@ -0,0 +1,30 @@
+package player
+
+import (
+    "io"
+    "strings"
+    "testing"
+
+    "dr2w.com/hf/model/seat"
+    "dr2w.com/hf/model/state"
+)
+
+func TestAcceptTakeback(t *testing.T) {
+    defer func(r io.Reader) { stdin = r }(stdin)
+    for _, test := range []struct {
+        name  string
+        input string
+        want  bool
+    }{
+        {"Yes", "y\n", true},
+        {"No", "n\n", false},
+        {"Closed", "", false},
+        {"NoAnswer", "\n\n\n\n", false},
+        {"SecondTime", "\nyes\n", true},
+    } {
+        stdin = strings.NewReader(test.input)
+        if got := (Stdio{seat.North}).AcceptTakeback(state.State{}, seat.East); got != test.want {
+            t.Errorf("%s: AcceptTakeback = %t, want %t", test.name, got, test.want)
+        }
+    }
+}
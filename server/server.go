@@ -0,0 +1,109 @@
+// Package server hosts a game of High Five for any mix of local Players,
+// such as AI bots, and remote ones connecting over the network.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+// Config describes a game to host.
+type Config struct {
+	// Players creates the Player for each Seat, in seat.Order. A nil
+	// Factory leaves the Seat to a player.Remote whose client connects to
+	// the server.
+	Players [4]player.Factory
+	// First is the Seat which deals first.
+	First seat.Seat
+	Seed  int64
+	// Rules are the rules the game is played under; the zero Config stands
+	// for rules.Standard.
+	Rules     rules.Config
+	Takebacks bool
+	// Reprompts and Forfeit decide what happens to a Player which answers
+	// badly, or not at all; see game.Game.
+	Reprompts int
+	Forfeit   bool
+	// Timeout bounds how long a remote Player is given to answer; zero
+	// waits forever.
+	Timeout time.Duration
+}
+
+// Remotes returns the Seats left to remote Players, in seat.Order.
+func (c Config) Remotes() []seat.Seat {
+	var remotes []seat.Seat
+	for i, st := range seat.Order {
+		if c.Players[i] == nil {
+			remotes = append(remotes, st)
+		}
+	}
+	return remotes
+}
+
+// Host accepts a connection on l for each remote Seat, seating the clients
+// in seat.Order as they connect, and then plays the game described by c to
+// its end. It returns the finished Game, or the Game as far as it got along
+// with the error which stopped it. Cancelling ctx while Host waits for
+// clients stops it waiting.
+//
+// Every connection is closed once the game is over.
+func Host(ctx context.Context, l net.Listener, c Config) (*game.Game, error) {
+	players := make([]player.Player, len(seat.Order))
+	var remotes []*player.Remote
+	defer func() {
+		for _, r := range remotes {
+			r.Close()
+		}
+	}()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-done:
+		}
+	}()
+	for i, st := range seat.Order {
+		if c.Players[i] != nil {
+			players[i] = c.Players[i](st)
+			continue
+		}
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return nil, fmt.Errorf("waiting for %s to connect: %w", st, err)
+		}
+		r, err := player.NewRemote(st, conn)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		r.Timeout = c.Timeout
+		log.Printf("%s connected from %s", st, conn.RemoteAddr())
+		remotes = append(remotes, r)
+		players[i] = r
+	}
+	rs := c.Rules
+	if rs == (rules.Config{}) {
+		rs = rules.Standard
+	}
+	g, err := game.NewWithRules(rs, c.Seed, c.First, players...)
+	if err != nil {
+		return nil, err
+	}
+	g.Takebacks = c.Takebacks
+	g.Reprompts = c.Reprompts
+	g.Forfeit = c.Forfeit
+	return g, g.Resolve()
+}
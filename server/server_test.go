@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"reflect"
+	"testing"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+func drw(seat.Seat) player.Player {
+	return ai.DRW
+}
+
+func TestHost(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	defer l.Close()
+	c := Config{Players: [4]player.Factory{drw, nil, drw, nil}, First: seat.North, Seed: 3}
+	if want := []seat.Seat{seat.East, seat.West}; !reflect.DeepEqual(c.Remotes(), want) {
+		t.Fatalf("Remotes = %v, want %v", c.Remotes(), want)
+	}
+	relayed := make(chan error, 2)
+	for range c.Remotes() {
+		go func() {
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				relayed <- err
+				return
+			}
+			defer conn.Close()
+			relayed <- player.Relay(conn, drw)
+		}()
+	}
+	g, err := Host(context.Background(), l, c)
+	if err != nil {
+		t.Fatalf("Host: %s\n%s", err, g)
+	}
+	if !g.Over() {
+		t.Errorf("game not over:\n%s", g)
+	}
+	for range c.Remotes() {
+		if err := <-relayed; err != nil {
+			t.Errorf("Relay: %s", err)
+		}
+	}
+}
+
+func TestHostCancelled(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Host(ctx, l, Config{}); err == nil {
+		t.Errorf("Host waited for clients after being cancelled")
+	}
+}
@@ -0,0 +1,126 @@
+// Package sim plays many games at once across a pool of workers. Every game
+// is isolated from the others: it has its own Players, its own source of
+// randomness and its own State, so that no two games share anything mutable.
+package sim
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"dr2w.com/hf/game"
+	"dr2w.com/hf/model/rules"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+// Config describes a batch of games.
+type Config struct {
+	// Players creates the Player for each Seat, in seat.Order. Each
+	// Factory is called afresh for every game.
+	Players [4]player.Factory
+	// First is the Seat which deals first in every game.
+	First seat.Seat
+	// Games is the number of games to play; game i is seeded with Seed+i.
+	Games int
+	Seed  int64
+	// Workers is the number of games played at once; values below one are
+	// treated as one.
+	Workers int
+	// Takebacks turns on the takeback rule in every game.
+	Takebacks bool
+	// Debug validates every State of every game; see game.Game.Debug.
+	Debug bool
+	// Rules are the rules every game is played under; the zero Config
+	// stands for rules.Standard.
+	Rules rules.Config
+}
+
+// Result is the outcome of a single game.
+type Result struct {
+	// Game is the index of the game within the batch.
+	Game int
+	Seed int64
+	// Score is the final score of the game, owned by the Result.
+	Score  map[seat.Team]int
+	Rounds int
+	// Err is set instead of Score if the game could not be completed.
+	Err error
+}
+
+// Run plays the games described by c and streams their Results, in the
+// order the games finish, over the returned channel, which is closed once
+// every game has finished. Cancelling ctx stops any further games from
+// starting, drops the Results not yet received and closes the channel once
+// the games being played finish, so the caller may stop receiving.
+func Run(ctx context.Context, c Config) <-chan Result {
+	workers := c.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	games := make(chan int)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range games {
+				select {
+				case results <- Play(c, i):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(games)
+		for i := 0; i < c.Games; i++ {
+			select {
+			case games <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// Play plays game i of the batch described by c on the calling goroutine.
+func Play(c Config, i int) Result {
+	r := Result{Game: i, Seed: c.Seed + int64(i)}
+	var players []player.Player
+	for j, st := range seat.Order {
+		if c.Players[j] == nil {
+			r.Err = fmt.Errorf("no player for %s", st)
+			return r
+		}
+		players = append(players, c.Players[j](st))
+	}
+	rs := c.Rules
+	if rs == (rules.Config{}) {
+		rs = rules.Standard
+	}
+	g, err := game.NewWithRules(rs, r.Seed, c.First, players...)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	g.Takebacks = c.Takebacks
+	g.Debug = c.Debug
+	if err := g.Resolve(); err != nil {
+		r.Err = fmt.Errorf("game %d (seed %d): %w", i, r.Seed, err)
+		return r
+	}
+	r.Score = make(map[seat.Team]int)
+	for tm, sc := range g.State.Score {
+		r.Score[tm] = sc
+	}
+	r.Rounds = g.State.Rounds
+	return r
+}
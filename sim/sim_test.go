@@ -0,0 +1,102 @@
+package sim
+
+import (
+	"context"
+	"io"
+	"log"
+	"runtime"
+	"testing"
+	"time"
+
+	"dr2w.com/hf/ai"
+	"dr2w.com/hf/model/seat"
+	"dr2w.com/hf/player"
+)
+
+func init() {
+	log.SetOutput(io.Discard)
+}
+
+func config(games, workers int) Config {
+	drw := func(seat.Seat) player.Player { return ai.DRW }
+	dumb := func(seat.Seat) player.Player { return ai.Dumb }
+	return Config{
+		Players: [4]player.Factory{drw, dumb, drw, dumb},
+		First:   seat.East,
+		Games:   games,
+		Seed:    100,
+		Workers: workers,
+	}
+}
+
+func collect(t *testing.T, results <-chan Result) map[int]Result {
+	byGame := make(map[int]Result)
+	for r := range results {
+		if r.Err != nil {
+			t.Errorf("Game %d: unexpected error: %s", r.Game, r.Err)
+		}
+		if _, ok := byGame[r.Game]; ok {
+			t.Errorf("Game %d reported twice", r.Game)
+		}
+		byGame[r.Game] = r
+	}
+	return byGame
+}
+
+// TestRunIsDeterministic plays the same batch serially and in parallel; run
+// with -race to also check that the games share nothing.
+func TestRunIsDeterministic(t *testing.T) {
+	serial := collect(t, Run(context.Background(), config(24, 1)))
+	parallel := collect(t, Run(context.Background(), config(24, 8)))
+	if len(serial) != 24 || len(parallel) != 24 {
+		t.Fatalf("want 24 results, got %d serially and %d in parallel", len(serial), len(parallel))
+	}
+	for i, s := range serial {
+		p := parallel[i]
+		if s.Seed != 100+int64(i) || p.Seed != s.Seed {
+			t.Errorf("Game %d: want seed %d, got %d and %d", i, 100+i, s.Seed, p.Seed)
+		}
+		if s.Rounds != p.Rounds || s.Score[seat.NorthSouth] != p.Score[seat.NorthSouth] || s.Score[seat.EastWest] != p.Score[seat.EastWest] {
+			t.Errorf("Game %d: serial %v (%d rounds) differs from parallel %v (%d rounds)",
+				i, s.Score, s.Rounds, p.Score, p.Rounds)
+		}
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := 0
+	for range Run(ctx, config(1000, 4)) {
+		n++
+	}
+	if n >= 1000 {
+		t.Errorf("played all %d games after cancellation", n)
+	}
+}
+
+// TestRunClosesWhenAbandoned cancels a batch and stops receiving its
+// Results, which must not leave the workers blocked sending them.
+func TestRunClosesWhenAbandoned(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	results := Run(ctx, config(1000, 4))
+	<-results
+	cancel()
+	deadline := time.Now().Add(10 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after cancellation", runtime.NumGoroutine()-before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := <-results; ok {
+		t.Errorf("results still open once the workers stopped")
+	}
+}
+
+func TestPlayWithoutPlayers(t *testing.T) {
+	if r := Play(Config{Games: 1}, 0); r.Err == nil {
+		t.Errorf("want an error for a game without players, got %v", r)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build sqlite
+
+package main
+
+// The pure-Go SQLite driver, so that -db sqlite:games.db works without cgo.
+// It is only built in with -tags sqlite, which keeps the default build free
+// of modules from outside the repository.
+import _ "modernc.org/sqlite"
@@ -0,0 +1,148 @@
+package main
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math"
+    "strconv"
+)
+
+// z is the critical value of the normal distribution used for the 95%
+// confidence intervals.
+const z = 1.96
+
+// Result is the outcome of a single game.
+type Result struct {
+    Seed       int64
+    NorthSouth int
+    EastWest   int
+    Rounds     int
+    // Err is set instead of the scores if the game could not be completed.
+    Err string `json:",omitempty"`
+}
+
+// Partnership summarizes the results of one partnership over a match.
+type Partnership struct {
+    Players [2]string
+    Wins    int
+    // WinRate is the fraction of completed games won, and WinRateCI the
+    // half-width of its 95% confidence interval.
+    WinRate   float64
+    WinRateCI float64
+    // Margin is the average of the partnership's score less its opponents'
+    // score, and MarginCI the half-width of its 95% confidence interval.
+    Margin   float64
+    MarginCI float64
+}
+
+// Summary aggregates the results of a match.
+type Summary struct {
+    Games      int
+    Failed     int
+    Ties       int
+    NorthSouth Partnership
+    EastWest   Partnership
+}
+
+// Summarize aggregates the results of a match between the given players,
+// named in seat.Order.
+func Summarize(players []string, results []Result) Summary {
+    s := Summary{
+        NorthSouth: Partnership{Players: [2]string{players[0], players[2]}},
+        EastWest:   Partnership{Players: [2]string{players[1], players[3]}},
+    }
+    var margins []float64
+    for _, r := range results {
+        if r.Err != "" {
+            s.Failed++
+            continue
+        }
+        s.Games++
+        switch {
+        case r.NorthSouth > r.EastWest:
+            s.NorthSouth.Wins++
+        case r.EastWest > r.NorthSouth:
+            s.EastWest.Wins++
+        default:
+            s.Ties++
+        }
+        margins = append(margins, float64(r.NorthSouth - r.EastWest))
+    }
+    if s.Games == 0 {
+        return s
+    }
+    n := float64(s.Games)
+    for _, p := range []*Partnership{&s.NorthSouth, &s.EastWest} {
+        p.WinRate = float64(p.Wins) / n
+        p.WinRateCI = z * math.Sqrt(p.WinRate * (1 - p.WinRate) / n)
+    }
+    mean, ci := meanCI(margins)
+    s.NorthSouth.Margin, s.NorthSouth.MarginCI = mean, ci
+    s.EastWest.Margin, s.EastWest.MarginCI = -mean, ci
+    return s
+}
+
+// meanCI returns the mean of the given samples and the half-width of its 95%
+// confidence interval.
+func meanCI(xs []float64) (float64, float64) {
+    var sum float64
+    for _, x := range xs {
+        sum += x
+    }
+    mean := sum / float64(len(xs))
+    if len(xs) < 2 {
+        return mean, 0
+    }
+    var ss float64
+    for _, x := range xs {
+        ss += (x - mean) * (x - mean)
+    }
+    sd := math.Sqrt(ss / float64(len(xs) - 1))
+    return mean, z * sd / math.Sqrt(float64(len(xs)))
+}
+
+// WriteText writes the Summary in a human readable form.
+func (s Summary) WriteText(w io.Writer) error {
+    if _, err := fmt.Fprintf(w, "Games: %d (%d failed, %d tied)\n", s.Games, s.Failed, s.Ties); err != nil {
+        return err
+    }
+    for _, p := range []struct {
+        name string
+        Partnership
+    }{{"North-South", s.NorthSouth}, {"East-West", s.EastWest}} {
+        _, err := fmt.Fprintf(w, "%-11s  %s/%s  wins %d (%.1f%% ± %.1f%%)  margin %+.1f ± %.1f\n",
+            p.name, p.Players[0], p.Players[1], p.Wins,
+            100 * p.WinRate, 100 * p.WinRateCI, p.Margin, p.MarginCI)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// WriteJSON writes the Summary as a JSON object.
+func (s Summary) WriteJSON(w io.Writer) error {
+    e := json.NewEncoder(w)
+    e.SetIndent("", "  ")
+    return e.Encode(s)
+}
+
+// WriteCSV writes one line per game: its seed, each partnership's score, the
+// number of rounds played and any error.
+func WriteCSV(w io.Writer, results []Result) error {
+    c := csv.NewWriter(w)
+    c.Write([]string{"seed", "north_south", "east_west", "rounds", "error"})
+    for _, r := range results {
+        c.Write([]string{
+            strconv.FormatInt(r.Seed, 10),
+            strconv.Itoa(r.NorthSouth),
+            strconv.Itoa(r.EastWest),
+            strconv.Itoa(r.Rounds),
+            r.Err,
+        })
+    }
+    c.Flush()
+    return c.Error()
+}
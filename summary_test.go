@@ -0,0 +1,42 @@
+package main
+
+import (
+    "bytes"
+    "math"
+    "strings"
+    "testing"
+)
+
+func TestSummarize(t *testing.T) {
+    results := []Result{
+        {Seed: 1, NorthSouth: 60, EastWest: 40},
+        {Seed: 2, NorthSouth: 30, EastWest: 54},
+        {Seed: 3, NorthSouth: 56, EastWest: 20},
+        {Seed: 4, NorthSouth: 53, EastWest: 53},
+        {Seed: 5, Err: "boom"},
+    }
+    s := Summarize([]string{"DRW", "Dumb", "DRW", "Dumb"}, results)
+    if s.Games != 4 || s.Failed != 1 || s.Ties != 1 {
+        t.Errorf("want 4 games, 1 failed and 1 tie, got %+v", s)
+    }
+    if s.NorthSouth.Wins != 2 || s.EastWest.Wins != 1 {
+        t.Errorf("want 2 wins to 1, got %d to %d", s.NorthSouth.Wins, s.EastWest.Wins)
+    }
+    if s.NorthSouth.WinRate != 0.5 || s.EastWest.WinRate != 0.25 {
+        t.Errorf("want win rates 0.5 and 0.25, got %v and %v", s.NorthSouth.WinRate, s.EastWest.WinRate)
+    }
+    if s.NorthSouth.Margin != 8 || s.EastWest.Margin != -8 {
+        t.Errorf("want margins 8 and -8, got %v and %v", s.NorthSouth.Margin, s.EastWest.Margin)
+    }
+    // Margins are 20, -24, 36 and 0, with a sample standard deviation of 25.92.
+    if want := 1.96 * 25.9229 / 2; math.Abs(s.NorthSouth.MarginCI - want) > 0.01 {
+        t.Errorf("want margin interval %.2f, got %.2f", want, s.NorthSouth.MarginCI)
+    }
+    var b bytes.Buffer
+    if err := s.WriteText(&b); err != nil {
+        t.Fatalf("WriteText: %s", err)
+    }
+    if !strings.Contains(b.String(), "DRW/DRW  wins 2 (50.0%") {
+        t.Errorf("unexpected text summary:\n%s", b.String())
+    }
+}